@@ -35,7 +35,7 @@ func main() {
 	}
 
 	httpClient := &http.Client{}
-	storage := storage.NewFSStorage(conf.WorkStoragePath)
+	storage := storage.NewFSStorage(conf.WorkStoragePath, storage.WithMaxMessageSize(conf.WorkMaxMessageSize))
 
 	awsConfig := aws.Config{
 		Region:      "ap-northeast-2",
@@ -58,6 +58,10 @@ func main() {
 		HTTPClient:     httpClient,
 		WorkStorage:    storage,
 		MetricStorage:  metricStorage,
+
+		ResultStoragePath: conf.ResultStoragePath,
+		ArtifactPath:      conf.ArtifactPath,
+		MaxAttempts:       conf.JobMaxAttempts,
 	}
 
 	srv := server.New(logger, serverOpts)
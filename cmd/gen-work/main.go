@@ -21,6 +21,8 @@ var (
 	num int
 
 	storePath         string
+	framed            bool
+	compression       storage.Compression
 	taskID, sectionID uuid.UUID
 
 	bodySchema []byte
@@ -30,6 +32,8 @@ var (
 	headers    map[string]string
 	templateID []byte
 	timeout    time.Duration
+
+	expected *work.Expected
 )
 
 func processParameters() {
@@ -45,12 +49,29 @@ func processParameters() {
 		_headers    = flag.String("headers", "", "http headers. seperated with comma. (e.g. headers=key=value,key=value")
 		_templateID = flag.String("templateID", "", "template id")
 		_timeout    = flag.Duration("timeout", 100*time.Millisecond, "request timeout")
+
+		_expectStatus    = flag.Uint("expectStatus", 0, "expected status code. used when templateID is not given")
+		_expectBodyPath  = flag.String("expectBody", "", "expected body file path")
+		_bodyMatch       = flag.String("bodyMatch", "exact", "expected body comparison mode (exact, json)")
+		_ignorePaths     = flag.String("ignorePaths", "", "json pointers ignored on json comparison. seperated with comma")
+		_unorderedArrays = flag.Bool("unorderedArrays", false, "treat arrays as unordered on json comparison")
+
+		_framed   = flag.Bool("framed", false, "write new files in framed format with checksums")
+		_compress = flag.String("compress", "", "compress files after generating (gzip, zstd). compressed files can't be appended")
 	)
 
 	flag.Parse()
 
 	num = *_num
 	storePath = *_storePath
+	framed = *_framed
+
+	c, err := storage.ParseCompression(*_compress)
+	if err != nil {
+		log.Fatal(err)
+	}
+	compression = c
+
 	method = *_method
 	path = *_path
 	timeout = *_timeout
@@ -93,6 +114,32 @@ func processParameters() {
 		templateID = id[:]
 	}
 
+	if *_expectStatus != 0 {
+		bodyMatch, ok := work.Expected_BodyMatch_value[strings.ToUpper(*_bodyMatch)]
+		if !ok {
+			log.Fatal("unknown body match")
+		}
+
+		expected = &work.Expected{
+			Status:          uint32(*_expectStatus),
+			BodyMatch:       work.Expected_BodyMatch(bodyMatch),
+			UnorderedArrays: *_unorderedArrays,
+		}
+
+		if *_expectBodyPath != "" {
+			b, err := os.ReadFile(*_expectBodyPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			expected.Body = b
+		}
+
+		if *_ignorePaths != "" {
+			expected.IgnorePaths = strings.Split(*_ignorePaths, ",")
+		}
+	}
+
 	if *_headers != "" {
 		kvPairs := strings.Split(*_headers, ",")
 		headerMap := make(map[string]string, len(kvPairs))
@@ -121,7 +168,12 @@ func main() {
 		generator = gen
 	}
 
-	storage := storage.NewFSStorage(storePath)
+	var opts []storage.Option
+	if framed {
+		opts = append(opts, storage.WithFramed())
+	}
+
+	storage := storage.NewFSStorage(storePath, opts...)
 
 	n := num
 	for i := 0; i < n; i++ {
@@ -144,8 +196,9 @@ func main() {
 				Headers: headers,
 				Body:    body,
 			},
-			TemplateId: templateID,
-			Timeout:    durationpb.New(timeout),
+			TemplateId:    templateID,
+			ExpectedValue: expected,
+			Timeout:       durationpb.New(timeout),
 		}
 
 		err := storage.InsertWork(context.Background(), taskID, sectionID, work)
@@ -153,4 +206,8 @@ func main() {
 			log.Fatal(err)
 		}
 	}
+
+	if err := storage.Compress(context.Background(), taskID, sectionID, compression); err != nil {
+		log.Fatal(err)
+	}
 }
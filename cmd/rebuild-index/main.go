@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/work/storage"
+)
+
+// rebuild-index builds index of work files written before indexing existed.
+// Without taskID and sectionID, every work file under the location is indexed.
+func main() {
+	taskIDString := flag.String("taskID", "", "")
+	sectionIDString := flag.String("sectionID", "", "")
+	location := flag.String("loc", "./", "storage location directory")
+
+	flag.Parse()
+
+	fs := storage.NewFSStorage(*location)
+
+	if *taskIDString != "" || *sectionIDString != "" {
+		taskID, err := uuid.Parse(*taskIDString)
+		if err != nil {
+			log.Fatal("taskID is malformed", err)
+		}
+
+		sectionID, err := uuid.Parse(*sectionIDString)
+		if err != nil {
+			log.Fatal("sectionID is malformed", err)
+		}
+
+		rebuild(fs, taskID, sectionID)
+		return
+	}
+
+	sections, err := fs.Sections()
+	if err != nil {
+		log.Fatal("finding sections", err)
+	}
+
+	for _, section := range sections {
+		rebuild(fs, section.TaskID, section.SectionID)
+	}
+}
+
+func rebuild(fs *storage.FSStorage, taskID, sectionID uuid.UUID) {
+	n, err := fs.RebuildIndex(context.Background(), taskID, sectionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s/%s: %v\n", taskID, sectionID, err)
+		return
+	}
+
+	fmt.Printf("%s/%s: %d works\n", taskID, sectionID, n)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/work/storage"
+)
+
+// verify-work reports byte offsets of damaged records in work and template files.
+// Without taskID and sectionID, every section under the location is verified.
+// It exits with status 1 if anything is damaged.
+func main() {
+	taskIDString := flag.String("taskID", "", "")
+	sectionIDString := flag.String("sectionID", "", "")
+	location := flag.String("loc", "./", "storage location directory")
+
+	flag.Parse()
+
+	fs := storage.NewFSStorage(*location)
+
+	var sections []storage.SectionKey
+
+	if *taskIDString != "" || *sectionIDString != "" {
+		taskID, err := uuid.Parse(*taskIDString)
+		if err != nil {
+			log.Fatal("taskID is malformed", err)
+		}
+
+		sectionID, err := uuid.Parse(*sectionIDString)
+		if err != nil {
+			log.Fatal("sectionID is malformed", err)
+		}
+
+		sections = append(sections, storage.SectionKey{TaskID: taskID, SectionID: sectionID})
+	} else {
+		found, err := fs.Sections()
+		if err != nil {
+			log.Fatal("finding sections", err)
+		}
+		sections = found
+	}
+
+	damaged := false
+	for _, section := range sections {
+		corruptions, err := fs.Verify(context.Background(), section.TaskID, section.SectionID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s/%s: %v\n", section.TaskID, section.SectionID, err)
+			damaged = true
+			continue
+		}
+
+		for _, c := range corruptions {
+			fmt.Printf("%s/%s/%s: offset %d: %s\n", section.TaskID, section.SectionID, c.File, c.Offset, c.Reason)
+		}
+
+		if len(corruptions) > 0 {
+			damaged = true
+		}
+	}
+
+	if damaged {
+		os.Exit(1)
+	}
+}
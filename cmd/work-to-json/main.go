@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
 	"github.com/oneee-playground/r2d2-tester/internal/work/storage"
 )
 
@@ -17,6 +18,8 @@ func main() {
 	sectionIDString := flag.String("sectionID", "", "")
 	sectionType := flag.String("type", string(job.TypeScenario), "section type")
 	location := flag.String("loc", "./", "storage location directory")
+	workIDString := flag.String("workID", "", "print only the work with the id")
+	from := flag.Int("from", 0, "skip works before the ordinal. index is required")
 
 	flag.Parse()
 
@@ -40,12 +43,26 @@ func main() {
 		log.Fatal("fetching tempaltes", err)
 	}
 
+	if *workIDString != "" {
+		workID, err := uuid.Parse(*workIDString)
+		if err != nil {
+			log.Fatal("workID is malformed", err)
+		}
+
+		work, err := storage.Get(context.Background(), taskID, sectionID, workID)
+		if err != nil {
+			log.Fatal("getting work", err)
+		}
+		printWork(work, templates)
+		return
+	}
+
 	cnt := 1
 	if *sectionType == string(job.TypeLoad) {
 		cnt = -3
 	}
 
-	stream, errchan := storage.Stream(context.Background(), taskID, sectionID)
+	stream, errchan := storage.StreamFrom(context.Background(), taskID, sectionID, *from)
 
 loop:
 	for cnt != 0 {
@@ -56,18 +73,22 @@ loop:
 			if !ok {
 				break loop
 			}
-			input, _ := json.Marshal(work.Input)
-			fmt.Printf("input: %s\n", input)
-
-			var expected []byte
-			if len(work.TemplateId) > 0 {
-				expected, _ = json.Marshal(templates[uuid.UUID(work.TemplateId)].SchemaTable)
-			} else {
-				expected, _ = json.Marshal(work.ExpectedValue)
-			}
-
-			fmt.Printf("expected: %s\n", expected)
+			printWork(work, templates)
 		}
 		cnt++
 	}
 }
+
+func printWork(w *work.Work, templates map[uuid.UUID]*work.Template) {
+	input, _ := json.Marshal(w.Input)
+	fmt.Printf("input: %s\n", input)
+
+	var expected []byte
+	if len(w.TemplateId) > 0 {
+		expected, _ = json.Marshal(templates[uuid.UUID(w.TemplateId)].SchemaTable)
+	} else {
+		expected, _ = json.Marshal(w.ExpectedValue)
+	}
+
+	fmt.Printf("expected: %s\n", expected)
+}
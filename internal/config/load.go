@@ -1,18 +1,39 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
 )
 
 func LoadFromEnv() {
 	WorkStoragePath = os.Getenv("WORK_STORAGE_PATH")
+	WorkMaxMessageSize = intFromEnv("WORK_MAX_MESSAGE_SIZE")
+	ResultStoragePath = os.Getenv("RESULT_STORAGE_PATH")
+	ArtifactPath = os.Getenv("ARTIFACT_PATH")
 
 	InfluxURL = os.Getenv("INFLUX_URL")
 	InfluxToken = os.Getenv("INFLUX_TOKEN")
 
 	JobQueueURL = os.Getenv("AWS_SQS_JOB_QUEUE_URL")
 	EventQueueURL = os.Getenv("AWS_SQS_TEST_EVENT_QUEUE_URL")
+	JobMaxAttempts = intFromEnv("JOB_MAX_ATTEMPTS")
 
 	AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
 	SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
 }
+
+// intFromEnv returns 0 if the variable is not set.
+func intFromEnv(key string) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("%s is malformed: %v", key, err)
+	}
+
+	return n
+}
@@ -2,6 +2,10 @@ package config
 
 var (
 	WorkStoragePath string
+	// WorkMaxMessageSize limits size of a stored work. Default is used if it is zero.
+	WorkMaxMessageSize int
+	ResultStoragePath  string
+	ArtifactPath       string
 )
 
 var (
@@ -12,6 +16,8 @@ var (
 var (
 	JobQueueURL   string
 	EventQueueURL string
+	// JobMaxAttempts is how many times a job aborted on our side is tried.
+	JobMaxAttempts int
 
 	AccessKeyID     string
 	SecretAccessKey string
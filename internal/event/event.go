@@ -5,15 +5,156 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/metric"
 )
 
 const Topic = "test"
 
+// Verdict is the outcome of a submission.
+type Verdict string
+
+const (
+	VerdictPassed Verdict = "passed"
+	VerdictFailed Verdict = "failed"
+	// VerdictAborted is set if the submission couldn't be judged by a failure on our side.
+	// Such event has neither success, score nor reports.
+	VerdictAborted Verdict = "aborted"
+)
+
 type TestEvent struct {
-	ID      uuid.UUID     `json:"id"`
+	ID      uuid.UUID `json:"id"`
+	Verdict Verdict   `json:"verdict"`
+	// Success is nil if the job was aborted.
+	Success *bool         `json:"success,omitempty"`
+	Took    time.Duration `json:"took"`
+	// Extra is the message of the first failure.
+	// Result has it in structured form.
+	Extra   string           `json:"extra"`
+	Result  Result           `json:"result"`
+	Latency []LatencySummary `json:"latency"`
+	// Thresholds are outcomes of sections with thresholds.
+	Thresholds []ThresholdResult `json:"thresholds"`
+	// Results is the path of result records of every executed work.
+	Results string `json:"results,omitempty"`
+	// Score is partial credit of the submission.
+	Score Score `json:"score"`
+	// Paths of JUnit XML and JSON reports.
+	JUnitReport string `json:"junitReport,omitempty"`
+	JSONReport  string `json:"jsonReport,omitempty"`
+}
+
+// ResultVersion is the version of Result schema.
+// It is bumped on every breaking change.
+const ResultVersion = 1
+
+// Stages where failures occur.
+const (
+	StageResource = "resource"
+	StageSetup    = "setup"
+	StageTest     = "test"
+	StageTeardown = "teardown"
+)
+
+type SectionStatus string
+
+const (
+	StatusPassed  SectionStatus = "passed"
+	StatusFailed  SectionStatus = "failed"
+	StatusSkipped SectionStatus = "skipped"
+)
+
+// Result is the outcome of a job.
+type Result struct {
+	Version int           `json:"version"`
 	Success bool          `json:"success"`
 	Took    time.Duration `json:"took"`
-	Extra   string        `json:"extra"`
+	// Failure is the first failure of the job.
+	Failure  *Failure        `json:"failure,omitempty"`
+	Sections []SectionResult `json:"sections"`
+}
+
+type SectionResult struct {
+	ID     uuid.UUID     `json:"id"`
+	Type   string        `json:"type"`
+	Status SectionStatus `json:"status"`
+
+	// Took is the duration of the test, excluding hooks.
+	Took     time.Duration `json:"took"`
+	Setup    time.Duration `json:"setup"`
+	Teardown time.Duration `json:"teardown"`
+
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+
+	// Failure is the first failure of the section.
+	Failure *Failure `json:"failure,omitempty"`
+	// TeardownFailure is given if teardown also failed after the test failed.
+	TeardownFailure *Failure `json:"teardownFailure,omitempty"`
+	// Load is given for LOAD section.
+	Load *LoadStats `json:"load,omitempty"`
+	// Throughput is achieved RPM of VIRTUAL_USER section.
+	Throughput float64 `json:"throughput,omitempty"`
+}
+
+type Failure struct {
+	Stage string `json:"stage"`
+	// Category tells why it failed. e.g. status, body, timeout, hook.
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	// WorkID is given if a work failed.
+	WorkID string `json:"workID,omitempty"`
+	// Internal is set if it is not the submission's fault. e.g. damaged work storage.
+	// The submission should be tested again rather than failed.
+	Internal bool `json:"internal,omitempty"`
+}
+
+type LoadStats struct {
+	Requests   int     `json:"requests"`
+	Failures   int     `json:"failures"`
+	MissedDues int     `json:"missedDues"`
+	RPM        float64 `json:"rpm"`
+	ErrorRate  float64 `json:"errorRate"`
+	// FailuresByCategory counts failures by their category.
+	FailuresByCategory map[string]int `json:"failuresByCategory,omitempty"`
+}
+
+// LatencySummary summarizes latencies of a section,
+// or of a stream inside it if Key is given.
+type LatencySummary struct {
+	SectionID uuid.UUID      `json:"sectionID"`
+	Scope     string         `json:"scope"`
+	Key       string         `json:"key,omitempty"`
+	Kind      string         `json:"kind"`
+	Summary   metric.Summary `json:"summary"`
+}
+
+// ThresholdResult tells whether a section met its thresholds.
+type ThresholdResult struct {
+	SectionID uuid.UUID `json:"sectionID"`
+	Passed    bool      `json:"passed"`
+	Breaches  []Breach  `json:"breaches"`
+}
+
+// Breach is a threshold that was not met.
+// Latencies are in nanoseconds.
+type Breach struct {
+	Threshold string  `json:"threshold"`
+	Limit     float64 `json:"limit"`
+	Actual    float64 `json:"actual"`
+}
+
+// Score is weighted average of section scores, from 0 to 1.
+type Score struct {
+	Total    float64        `json:"total"`
+	Sections []SectionScore `json:"sections"`
+}
+
+type SectionScore struct {
+	SectionID uuid.UUID `json:"sectionID"`
+	Rule      string    `json:"rule"`
+	Weight    float64   `json:"weight"`
+	// Score is from 0 to 1.
+	Score float64 `json:"score"`
 }
 
 type Publisher interface {
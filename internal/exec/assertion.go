@@ -0,0 +1,193 @@
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/oneee-playground/r2d2-tester/internal/util/jsonpointer"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+type assertion struct {
+	*work.Assertion
+	re *regexp.Regexp
+}
+
+var assertionPatterns = &patternCache{patterns: make(map[string]*regexp.Regexp)}
+
+func compileAssertions(assertions []*work.Assertion) ([]assertion, error) {
+	compiled := make([]assertion, len(assertions))
+
+	for idx, a := range assertions {
+		compiled[idx] = assertion{Assertion: a}
+
+		if a.Operator == work.Assertion_REGEX {
+			re, err := assertionPatterns.compile(string(a.Value))
+			if err != nil {
+				return nil, errors.Wrapf(err, "compiling pattern for assertion %s", a.Path)
+			}
+			compiled[idx].re = re
+		}
+	}
+
+	return compiled, nil
+}
+
+// evalAssertions evaluates every assertion against the body.
+// It reports all failing assertions at once, with outcome of each one.
+func evalAssertions(body []byte, assertions []assertion) ([]*work.AssertionResult, error) {
+	if len(assertions) == 0 {
+		return nil, nil
+	}
+
+	doc, err := jsonpointer.Decode(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing body for assertions")
+	}
+
+	results := make([]*work.AssertionResult, len(assertions))
+
+	var failures []string
+	for idx, assertion := range assertions {
+		results[idx] = &work.AssertionResult{
+			Path:     assertion.Path,
+			Operator: assertion.Operator,
+			Passed:   true,
+		}
+
+		if err := evalAssertion(doc, assertion); err != nil {
+			results[idx].Passed = false
+			results[idx].Message = err.Error()
+
+			failures = append(failures, fmt.Sprintf("%s: %s", assertion.Path, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return results, errors.Errorf(
+			"failed assertions (%d/%d): %s",
+			len(failures), len(assertions), strings.Join(failures, "; "),
+		)
+	}
+
+	return results, nil
+}
+
+func evalAssertion(doc any, assertion assertion) error {
+	actual, err := jsonpointer.Get(doc, assertion.Path)
+	if assertion.Operator == work.Assertion_ABSENT {
+		if err == nil {
+			return errors.Errorf("expected absent, actual: %s", stringifyJSON(actual))
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch assertion.Operator {
+	case work.Assertion_EQUALS, work.Assertion_NOT_EQUALS:
+		operand, err := jsonpointer.Decode(assertion.Value)
+		if err != nil {
+			return errors.Wrap(err, "parsing operand")
+		}
+
+		equal := jsonEqual(actual, operand, false)
+		if assertion.Operator == work.Assertion_EQUALS && !equal {
+			return errors.Errorf("expected: %s, actual: %s", assertion.Value, stringifyJSON(actual))
+		}
+		if assertion.Operator == work.Assertion_NOT_EQUALS && equal {
+			return errors.Errorf("expected not: %s", assertion.Value)
+		}
+	case work.Assertion_REGEX:
+		s, ok := actual.(string)
+		if !ok {
+			s = stringifyJSON(actual)
+		}
+
+		if !assertion.re.MatchString(s) {
+			return errors.Errorf("expected to match %q, actual: %s", assertion.Value, s)
+		}
+	case work.Assertion_RANGE:
+		n, ok := actual.(json.Number)
+		if !ok {
+			return errors.Errorf("expected number, actual: %s", jsonType(actual))
+		}
+
+		f, err := n.Float64()
+		if err != nil {
+			return errors.Wrap(err, "parsing number")
+		}
+
+		if err := evalBounds(f, assertion); err != nil {
+			return err
+		}
+	case work.Assertion_TYPE_OF:
+		if got := jsonType(actual); got != string(assertion.Value) {
+			return errors.Errorf("expected type: %s, actual: %s", assertion.Value, got)
+		}
+	case work.Assertion_LENGTH:
+		arr, ok := actual.([]any)
+		if !ok {
+			return errors.Errorf("expected array, actual: %s", jsonType(actual))
+		}
+
+		if err := evalBounds(float64(len(arr)), assertion); err != nil {
+			return errors.Wrap(err, "length")
+		}
+	case work.Assertion_CONTAINS:
+		if err := evalContains(actual, assertion.Value); err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unknown operator: %s", assertion.Operator)
+	}
+
+	return nil
+}
+
+func evalBounds(val float64, assertion assertion) error {
+	if assertion.Min != nil && val < *assertion.Min {
+		return errors.Errorf("expected >= %v, actual: %v", *assertion.Min, val)
+	}
+	if assertion.Max != nil && val > *assertion.Max {
+		return errors.Errorf("expected <= %v, actual: %v", *assertion.Max, val)
+	}
+
+	return nil
+}
+
+func evalContains(actual any, operand []byte) error {
+	switch v := actual.(type) {
+	case string:
+		// Operand may either be a JSON string or raw substring.
+		var sub string
+		if err := json.Unmarshal(operand, &sub); err != nil {
+			sub = string(operand)
+		}
+
+		if !strings.Contains(v, sub) {
+			return errors.Errorf("expected to contain %q, actual: %q", sub, v)
+		}
+	case []any:
+		elem, err := jsonpointer.Decode(operand)
+		if err != nil {
+			return errors.Wrap(err, "parsing operand")
+		}
+
+		for _, item := range v {
+			if jsonEqual(item, elem, false) {
+				return nil
+			}
+		}
+
+		return errors.Errorf("expected to contain element %s", operand)
+	default:
+		return errors.Errorf("expected string or array, actual: %s", jsonType(actual))
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEvalAssertions(t *testing.T) {
+	body := []byte(`
+	{
+		"id": 3,
+		"title": "First Board!",
+		"price": 1.5,
+		"createdAt": "2024-07-01T00:00:00Z",
+		"tags": ["a", "b"],
+		"author": {"name": "foo"}
+	}
+	`)
+
+	testcases := []struct {
+		desc      string
+		assertion *work.Assertion
+		wantErr   bool
+	}{
+		{
+			desc:      "equals number",
+			assertion: &work.Assertion{Path: "/id", Operator: work.Assertion_EQUALS, Value: []byte("3.0")},
+		},
+		{
+			desc:      "equals object",
+			assertion: &work.Assertion{Path: "/author", Operator: work.Assertion_EQUALS, Value: []byte(`{"name":"foo"}`)},
+		},
+		{
+			desc:      "equals unmatch",
+			assertion: &work.Assertion{Path: "/title", Operator: work.Assertion_EQUALS, Value: []byte(`"Second"`)},
+			wantErr:   true,
+		},
+		{
+			desc:      "not equals",
+			assertion: &work.Assertion{Path: "/title", Operator: work.Assertion_NOT_EQUALS, Value: []byte(`""`)},
+		},
+		{
+			desc:      "regex",
+			assertion: &work.Assertion{Path: "/createdAt", Operator: work.Assertion_REGEX, Value: []byte(`^\d{4}-\d{2}-\d{2}T`)},
+		},
+		{
+			desc:      "range",
+			assertion: &work.Assertion{Path: "/price", Operator: work.Assertion_RANGE, Min: proto.Float64(1), Max: proto.Float64(2)},
+		},
+		{
+			desc:      "range out of bounds",
+			assertion: &work.Assertion{Path: "/price", Operator: work.Assertion_RANGE, Max: proto.Float64(1)},
+			wantErr:   true,
+		},
+		{
+			desc:      "type of",
+			assertion: &work.Assertion{Path: "/tags", Operator: work.Assertion_TYPE_OF, Value: []byte("array")},
+		},
+		{
+			desc:      "length",
+			assertion: &work.Assertion{Path: "/tags", Operator: work.Assertion_LENGTH, Min: proto.Float64(2), Max: proto.Float64(2)},
+		},
+		{
+			desc:      "length of non-array",
+			assertion: &work.Assertion{Path: "/title", Operator: work.Assertion_LENGTH, Min: proto.Float64(1)},
+			wantErr:   true,
+		},
+		{
+			desc:      "contains element",
+			assertion: &work.Assertion{Path: "/tags", Operator: work.Assertion_CONTAINS, Value: []byte(`"b"`)},
+		},
+		{
+			desc:      "contains substring",
+			assertion: &work.Assertion{Path: "/title", Operator: work.Assertion_CONTAINS, Value: []byte("Board")},
+		},
+		{
+			desc:      "absent",
+			assertion: &work.Assertion{Path: "/password", Operator: work.Assertion_ABSENT},
+		},
+		{
+			desc:      "absent but present",
+			assertion: &work.Assertion{Path: "/id", Operator: work.Assertion_ABSENT},
+			wantErr:   true,
+		},
+		{
+			desc:      "missing path",
+			assertion: &work.Assertion{Path: "/missing", Operator: work.Assertion_TYPE_OF, Value: []byte("string")},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			results, err := evalAssertions(body, mustCompileAssertions(t, tc.assertion))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if assert.Len(t, results, 1) {
+				assert.Equal(t, !tc.wantErr, results[0].Passed)
+			}
+		})
+	}
+}
+
+func TestEvalAssertionsReportsEveryFailure(t *testing.T) {
+	assertions := []*work.Assertion{
+		{Path: "/a", Operator: work.Assertion_EQUALS, Value: []byte("1")},
+		{Path: "/b", Operator: work.Assertion_EQUALS, Value: []byte("2")},
+		{Path: "/c", Operator: work.Assertion_ABSENT},
+	}
+
+	results, err := evalAssertions([]byte(`{"a":0,"b":0}`), mustCompileAssertions(t, assertions...))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "/a:")
+		assert.Contains(t, err.Error(), "/b:")
+		assert.NotContains(t, err.Error(), "/c:")
+	}
+
+	passed := make([]bool, len(results))
+	for idx, result := range results {
+		passed[idx] = result.Passed
+	}
+	assert.Equal(t, []bool{false, false, true}, passed)
+}
+
+func TestEvalAssertionsTrailingData(t *testing.T) {
+	assertions := []*work.Assertion{
+		{Path: "/id", Operator: work.Assertion_EQUALS, Value: []byte("1")},
+	}
+
+	_, err := evalAssertions([]byte(`{"id":1}<html>`), mustCompileAssertions(t, assertions...))
+	assert.Error(t, err)
+}
+
+func TestCompileAssertionsInvalidPattern(t *testing.T) {
+	_, err := compileAssertions([]*work.Assertion{
+		{Path: "/id", Operator: work.Assertion_REGEX, Value: []byte("(")},
+	})
+	assert.Error(t, err)
+}
+
+func TestCompileAssertionsCached(t *testing.T) {
+	assertions := []*work.Assertion{
+		{Path: "/createdAt", Operator: work.Assertion_REGEX, Value: []byte(`^\d{4}`)},
+	}
+
+	first, err := compileAssertions(assertions)
+	require.NoError(t, err)
+
+	second, err := compileAssertions(assertions)
+	require.NoError(t, err)
+
+	assert.Same(t, first[0].re, second[0].re)
+}
+
+func TestInvalidAssertionPatternIsWorkDefinitionFailure(t *testing.T) {
+	w := &worker{httpClient: http.DefaultClient}
+
+	_, err := w.do(context.Background(), &work.Work{
+		Input: &work.Input{Method: "GET", Path: "/"},
+		ExpectedValue: &work.Expected{
+			Assertions: []*work.Assertion{{Path: "/id", Operator: work.Assertion_REGEX, Value: []byte("(")}},
+		},
+	})
+	assert.Equal(t, failureDefinition, categoryOf(err))
+}
+
+func mustCompileAssertions(t *testing.T, assertions ...*work.Assertion) []assertion {
+	t.Helper()
+
+	compiled, err := compileAssertions(assertions)
+	require.NoError(t, err)
+
+	return compiled
+}
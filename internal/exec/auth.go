@@ -0,0 +1,132 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/util/jsonpointer"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+const bearerPrefix = "Bearer "
+
+// authenticator obtains token with the login request,
+// and attaches it to requests. It is safe for concurrent use.
+type authenticator struct {
+	conf   *job.Auth
+	sender *worker
+
+	mu    sync.RWMutex
+	token string
+
+	// refreshMu serializes logins for refresh.
+	refreshMu sync.Mutex
+}
+
+func newAuthenticator(conf *job.Auth, target *process, httpClient *http.Client) *authenticator {
+	return &authenticator{
+		conf:   conf,
+		sender: &worker{target: target, httpClient: httpClient},
+	}
+}
+
+func (a *authenticator) login(ctx context.Context) error {
+	input := &work.Input{
+		Method:  a.conf.Method,
+		Path:    a.conf.Path,
+		Headers: a.conf.Headers,
+		Body:    []byte(a.conf.Body),
+	}
+
+	res, err := a.sender.sendRequest(ctx, input)
+	if err != nil {
+		return errors.Wrap(err, "sending login request")
+	}
+
+	body, err := readBody(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode/100 != 2 {
+		return errors.Errorf("login failed with status code: %d", res.StatusCode)
+	}
+
+	token, err := a.extractToken(res.Header, body)
+	if err != nil {
+		return errors.Wrap(err, "extracting token")
+	}
+
+	a.mu.Lock()
+	a.token = token
+	a.mu.Unlock()
+
+	return nil
+}
+
+func (a *authenticator) extractToken(header http.Header, body []byte) (string, error) {
+	var token string
+
+	if a.conf.TokenHeader != "" {
+		token = strings.TrimPrefix(header.Get(a.conf.TokenHeader), bearerPrefix)
+	} else {
+		doc, err := jsonpointer.Decode(body)
+		if err != nil {
+			return "", err
+		}
+
+		val, err := jsonpointer.Get(doc, a.conf.TokenPointer)
+		if err != nil {
+			return "", err
+		}
+
+		s, ok := val.(string)
+		if !ok {
+			return "", errors.Errorf("token is not a string: %s", jsonType(val))
+		}
+		token = s
+	}
+
+	if token == "" {
+		return "", errors.New("empty token")
+	}
+
+	return token, nil
+}
+
+// authorize attaches token unless the request has its own authorization.
+// It returns the attached token.
+func (a *authenticator) authorize(request *http.Request) string {
+	if request.Header.Get("Authorization") != "" {
+		return ""
+	}
+
+	a.mu.RLock()
+	token := a.token
+	a.mu.RUnlock()
+
+	request.Header.Set("Authorization", bearerPrefix+token)
+
+	return token
+}
+
+// refresh logs in again if the token is still the stale one.
+// Workers refreshing at once wait for the first login, and reuse its token.
+func (a *authenticator) refresh(ctx context.Context, stale string) error {
+	a.refreshMu.Lock()
+	defer a.refreshMu.Unlock()
+
+	a.mu.RLock()
+	current := a.token
+	a.mu.RUnlock()
+
+	if current != stale {
+		return nil
+	}
+
+	return a.login(ctx)
+}
@@ -0,0 +1,134 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTarget starts server and returns it as process.
+func newTestTarget(t *testing.T, handler http.Handler) *process {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	require.NoError(t, err)
+
+	return &process{Hostname: host, Port: uint16(p)}
+}
+
+func TestAuthenticator(t *testing.T) {
+	var issued atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		n := issued.Add(1)
+		w.Header().Set("Authorization", fmt.Sprintf("Bearer header-token-%d", n))
+		fmt.Fprintf(w, `{"data":{"token":"token-%d"}}`, n)
+	})
+	mux.HandleFunc("GET /me", func(w http.ResponseWriter, r *http.Request) {
+		// Only the latest token is valid.
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", issued.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	target := newTestTarget(t, mux)
+
+	conf := &job.Auth{
+		Method:                "POST",
+		Path:                  "/login",
+		TokenPointer:          "/data/token",
+		RefreshOnUnauthorized: true,
+	}
+
+	auth := newAuthenticator(conf, target, http.DefaultClient)
+	require.NoError(t, auth.login(context.Background()))
+	assert.Equal(t, "token-1", auth.token)
+
+	w := &worker{target: target, auth: auth, httpClient: http.DefaultClient}
+
+	res, err := w.sendRequest(context.Background(), &work.Input{Method: "GET", Path: "/me"})
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Token gets expired by another login.
+	issued.Add(1)
+
+	res, err = w.sendRequest(context.Background(), &work.Input{Method: "GET", Path: "/me"})
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "token-3", auth.token)
+
+	// Explicit authorization is left untouched and never refreshed.
+	res, err = w.sendRequest(context.Background(), &work.Input{
+		Method:  "GET",
+		Path:    "/me",
+		Headers: map[string]string{"Authorization": "Bearer invalid"},
+	})
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, int32(3), issued.Load())
+
+	t.Run("token from header", func(t *testing.T) {
+		conf := &job.Auth{Method: "POST", Path: "/login", TokenHeader: "Authorization"}
+
+		auth := newAuthenticator(conf, target, http.DefaultClient)
+		require.NoError(t, auth.login(context.Background()))
+		assert.Equal(t, "header-token-4", auth.token)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		conf := &job.Auth{Method: "POST", Path: "/login", TokenPointer: "/token"}
+
+		auth := newAuthenticator(conf, target, http.DefaultClient)
+		assert.Error(t, auth.login(context.Background()))
+	})
+}
+
+func TestAuthenticatorConcurrentRefresh(t *testing.T) {
+	var issued atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token":"token-%d"}`, issued.Add(1))
+	})
+
+	target := newTestTarget(t, mux)
+
+	conf := &job.Auth{Method: "POST", Path: "/login", TokenPointer: "/token"}
+
+	auth := newAuthenticator(conf, target, http.DefaultClient)
+	require.NoError(t, auth.login(context.Background()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, auth.refresh(context.Background(), "token-1"))
+		}()
+	}
+	wg.Wait()
+
+	// Only the first refresh logs in.
+	assert.Equal(t, int32(2), issued.Load())
+	assert.Equal(t, "token-2", auth.token)
+}
@@ -0,0 +1,115 @@
+package exec
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+// sessionJar is a cookie jar owned by a session client. It can be cleared in place.
+type sessionJar struct {
+	mu  sync.RWMutex
+	jar *cookiejar.Jar
+}
+
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	j.jar.SetCookies(u, cookies)
+}
+
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.jar.Cookies(u)
+}
+
+func (j *sessionJar) clear() error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return errors.Wrap(err, "creating cookie jar")
+	}
+
+	j.mu.Lock()
+	j.jar = jar
+	j.mu.Unlock()
+
+	return nil
+}
+
+// newSessionClient returns a copy of base with its own session.
+// Base is left untouched.
+func newSessionClient(base *http.Client) (*http.Client, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating cookie jar")
+	}
+
+	client := *base
+	client.Jar = &sessionJar{jar: jar}
+
+	return &client, nil
+}
+
+// clearCookies drops every cookie in the client's session.
+// It does nothing if the client has no session made by newSessionClient.
+func clearCookies(client *http.Client) error {
+	jar, ok := client.Jar.(*sessionJar)
+	if !ok {
+		return nil
+	}
+
+	return jar.clear()
+}
+
+func evalCookies(cookies []*http.Cookie, matchers []*work.CookieMatcher) error {
+	for _, matcher := range matchers {
+		if err := matchCookie(cookies, matcher); err != nil {
+			return errors.Wrapf(err, "cookie matcher for %s failed", matcher.Name)
+		}
+	}
+
+	return nil
+}
+
+func matchCookie(cookies []*http.Cookie, matcher *work.CookieMatcher) error {
+	var found *http.Cookie
+	for _, cookie := range cookies {
+		if cookie.Name == matcher.Name {
+			found = cookie
+		}
+	}
+
+	if matcher.Absent {
+		if found != nil {
+			return errors.Errorf("cookie is set. actual: %s", found)
+		}
+		return nil
+	}
+
+	if found == nil {
+		return errors.New("cookie is not set")
+	}
+
+	var mismatches []string
+	if matcher.Value != nil && found.Value != *matcher.Value {
+		mismatches = append(mismatches, fmt.Sprintf("value expected: %q", *matcher.Value))
+	}
+	if matcher.HttpOnly && !found.HttpOnly {
+		mismatches = append(mismatches, "expected HttpOnly")
+	}
+	if matcher.Secure && !found.Secure {
+		mismatches = append(mismatches, "expected Secure")
+	}
+
+	if len(mismatches) > 0 {
+		return errors.Errorf("%v. actual: %s", mismatches, found)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package exec
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEvalCookies(t *testing.T) {
+	cookies := []*http.Cookie{
+		{Name: "session", Value: "foo", HttpOnly: true},
+		{Name: "theme", Value: "dark"},
+	}
+
+	testcases := []struct {
+		desc    string
+		matcher *work.CookieMatcher
+		wantErr bool
+	}{
+		{
+			desc:    "set with HttpOnly",
+			matcher: &work.CookieMatcher{Name: "session", HttpOnly: true},
+		},
+		{
+			desc:    "value",
+			matcher: &work.CookieMatcher{Name: "theme", Value: proto.String("dark")},
+		},
+		{
+			desc:    "unmatching value",
+			matcher: &work.CookieMatcher{Name: "theme", Value: proto.String("light")},
+			wantErr: true,
+		},
+		{
+			desc:    "missing HttpOnly",
+			matcher: &work.CookieMatcher{Name: "theme", HttpOnly: true},
+			wantErr: true,
+		},
+		{
+			desc:    "missing Secure",
+			matcher: &work.CookieMatcher{Name: "session", Secure: true},
+			wantErr: true,
+		},
+		{
+			desc:    "not set",
+			matcher: &work.CookieMatcher{Name: "token"},
+			wantErr: true,
+		},
+		{
+			desc:    "absent",
+			matcher: &work.CookieMatcher{Name: "token", Absent: true},
+		},
+		{
+			desc:    "absent but set",
+			matcher: &work.CookieMatcher{Name: "session", Absent: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := evalCookies(cookies, []*work.CookieMatcher{tc.matcher})
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSessionClient(t *testing.T) {
+	base := &http.Client{}
+
+	client, err := newSessionClient(base)
+	require.NoError(t, err)
+
+	assert.Nil(t, base.Jar)
+
+	u, err := url.Parse("http://app:4000/")
+	require.NoError(t, err)
+
+	client.Jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "foo"}})
+	assert.Len(t, client.Jar.Cookies(u), 1)
+
+	require.NoError(t, clearCookies(client))
+	assert.Empty(t, client.Jar.Cookies(u))
+
+	// Clients without session are left untouched.
+	require.NoError(t, clearCookies(base))
+	assert.Nil(t, base.Jar)
+
+	// Nor are ones with their own jar, which may be shared.
+	shared, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	shared.SetCookies(u, []*http.Cookie{{Name: "session", Value: "foo"}})
+
+	base.Jar = shared
+	require.NoError(t, clearCookies(base))
+	assert.Same(t, shared, base.Jar)
+	assert.Len(t, shared.Cookies(u), 1)
+}
@@ -0,0 +1,287 @@
+package exec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oneee-playground/r2d2-tester/internal/util/jsonpointer"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// maxDiffLines bounds lines of a diff put into an error.
+	maxDiffLines = 30
+	// maxTextDiffLines bounds lines of bodies compared line by line.
+	maxTextDiffLines = 1000
+	// diffContext is the number of unchanged lines around changes.
+	diffContext = 3
+)
+
+// diffJSON reports differences between decoded JSON values path by path.
+// If unordered is set, arrays are compared as multisets.
+func diffJSON(expected, actual any, unordered bool) []string {
+	var diffs []string
+	diffJSONAt("", expected, actual, unordered, &diffs)
+	return diffs
+}
+
+func diffJSONAt(path string, expected, actual any, unordered bool, diffs *[]string) {
+	at := path
+	if at == "" {
+		at = "(root)"
+	}
+
+	if jsonType(expected) != jsonType(actual) {
+		*diffs = append(*diffs, fmt.Sprintf(
+			"%s: type changed. expected: %s, actual: %s", at, jsonType(expected), jsonType(actual),
+		))
+		return
+	}
+
+	switch x := expected.(type) {
+	case map[string]any:
+		y := actual.(map[string]any)
+
+		keys := make([]string, 0, len(x)+len(y))
+		for key := range x {
+			keys = append(keys, key)
+		}
+		for key := range y {
+			if _, ok := x[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			child := path + jsonpointer.Join(key)
+
+			expectedVal, inExpected := x[key]
+			actualVal, inActual := y[key]
+
+			switch {
+			case !inActual:
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing key", child))
+			case !inExpected:
+				*diffs = append(*diffs, fmt.Sprintf("%s: extra key", child))
+			default:
+				diffJSONAt(child, expectedVal, actualVal, unordered, diffs)
+			}
+		}
+	case []any:
+		y := actual.([]any)
+
+		if unordered {
+			diffJSONUnordered(at, x, y, diffs)
+			return
+		}
+
+		if len(x) != len(y) {
+			*diffs = append(*diffs, fmt.Sprintf(
+				"%s: array length changed. expected: %d, actual: %d", at, len(x), len(y),
+			))
+		}
+
+		for idx := 0; idx < min(len(x), len(y)); idx++ {
+			diffJSONAt(fmt.Sprintf("%s/%d", path, idx), x[idx], y[idx], false, diffs)
+		}
+	default:
+		if !jsonEqual(expected, actual, false) {
+			*diffs = append(*diffs, fmt.Sprintf(
+				"%s: value changed. expected: %s, actual: %s", at, quoteJSON(expected), quoteJSON(actual),
+			))
+		}
+	}
+}
+
+func diffJSONUnordered(at string, expected, actual []any, diffs *[]string) {
+	if len(expected) != len(actual) {
+		*diffs = append(*diffs, fmt.Sprintf(
+			"%s: array length changed. expected: %d, actual: %d", at, len(expected), len(actual),
+		))
+	}
+
+	used := make([]bool, len(actual))
+
+outer:
+	for _, a := range expected {
+		for idx, b := range actual {
+			if !used[idx] && jsonEqual(a, b, true) {
+				used[idx] = true
+				continue outer
+			}
+		}
+		*diffs = append(*diffs, fmt.Sprintf("%s: missing element %s", at, quoteJSON(a)))
+	}
+
+	for idx, b := range actual {
+		if !used[idx] {
+			*diffs = append(*diffs, fmt.Sprintf("%s: extra element %s", at, quoteJSON(b)))
+		}
+	}
+}
+
+// quoteJSON is like stringifyJSON, but keeps strings quoted.
+func quoteJSON(val any) string {
+	if s, ok := val.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return stringifyJSON(val)
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// diffText returns unified line diff from expected to actual.
+func diffText(expected, actual string) string {
+	a, b := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+
+	if len(a) > maxTextDiffLines || len(b) > maxTextDiffLines {
+		for idx := 0; idx < min(len(a), len(b)); idx++ {
+			if a[idx] != b[idx] {
+				return fmt.Sprintf("bodies are too long to diff. first difference at line %d", idx+1)
+			}
+		}
+		return fmt.Sprintf("bodies are too long to diff. line count differs: %d, %d", len(a), len(b))
+	}
+
+	ops := diffLines(a, b)
+
+	lines := []string{"--- expected", "+++ actual"}
+
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough.
+		end := start
+		for idx := start; idx < len(ops); idx++ {
+			if ops[idx].kind != ' ' {
+				end = idx + 1
+			} else if idx-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from, to := max(start-diffContext, 0), min(end+diffContext, len(ops))
+
+		// Line numbers are 1-based.
+		aLine, bLine := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				aLine++
+			}
+			if op.kind != '-' {
+				bLine++
+			}
+		}
+
+		var aCount, bCount int
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("@@ -%d,%d +%d,%d @@", aLine, aCount, bLine, bCount))
+		for _, op := range ops[from:to] {
+			lines = append(lines, string(op.kind)+op.line)
+		}
+
+		start = to
+	}
+
+	return strings.Join(truncateLines(lines), "\n")
+}
+
+// diffLines finds the longest common subsequence of lines,
+// and returns operations turning a into b.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of LCS of a[i:] and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+// diffSchema groups schema validation errors by JSON pointer of the field.
+func diffSchema(errs []gojsonschema.ResultError) []string {
+	grouped := make(map[string][]string)
+	for _, err := range errs {
+		path := strings.TrimPrefix(err.Context().String("/"), "(root)")
+		if path == "" {
+			path = "(root)"
+		}
+
+		grouped[path] = append(grouped[path], err.Description())
+	}
+
+	paths := make([]string, 0, len(grouped))
+	for path := range grouped {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	lines := make([]string, len(paths))
+	for idx, path := range paths {
+		lines[idx] = fmt.Sprintf("%s: %s", path, strings.Join(grouped[path], "; "))
+	}
+
+	return lines
+}
+
+// truncateLines keeps first maxDiffLines lines.
+func truncateLines(lines []string) []string {
+	if len(lines) <= maxDiffLines {
+		return lines
+	}
+
+	more := len(lines) - maxDiffLines
+	return append(lines[:maxDiffLines:maxDiffLines], fmt.Sprintf("... and %d more", more))
+}
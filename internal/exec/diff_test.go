@@ -0,0 +1,130 @@
+package exec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oneee-playground/r2d2-tester/internal/util/jsonpointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestDiffJSON(t *testing.T) {
+	decode := func(s string) any {
+		doc, err := jsonpointer.Decode([]byte(s))
+		require.NoError(t, err)
+		return doc
+	}
+
+	testcases := []struct {
+		desc      string
+		expected  string
+		actual    string
+		unordered bool
+		diffs     []string
+	}{
+		{
+			desc:     "equal",
+			expected: `{"id":1,"tags":["a"]}`,
+			actual:   `{"tags":["a"],"id":1.0}`,
+		},
+		{
+			desc:     "missing, extra and changed",
+			expected: `{"id":1,"title":"foo","user":{"name":"kim"}}`,
+			actual:   `{"id":2,"user":{"name":"lee"},"createdAt":"2024-07-01"}`,
+			diffs: []string{
+				`/createdAt: extra key`,
+				`/id: value changed. expected: 1, actual: 2`,
+				`/title: missing key`,
+				`/user/name: value changed. expected: "kim", actual: "lee"`,
+			},
+		},
+		{
+			desc:     "array length",
+			expected: `{"items":[1,2,3]}`,
+			actual:   `{"items":[1,5]}`,
+			diffs: []string{
+				`/items: array length changed. expected: 3, actual: 2`,
+				`/items/1: value changed. expected: 2, actual: 5`,
+			},
+		},
+		{
+			desc:     "type",
+			expected: `{"a/b":{}}`,
+			actual:   `{"a/b":[]}`,
+			diffs:    []string{`/a~1b: type changed. expected: object, actual: array`},
+		},
+		{
+			desc:      "unordered",
+			expected:  `[1,2,2]`,
+			actual:    `[2,1,3]`,
+			unordered: true,
+			diffs: []string{
+				`(root): missing element 2`,
+				`(root): extra element 3`,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			diffs := diffJSON(decode(tc.expected), decode(tc.actual), tc.unordered)
+			assert.Equal(t, tc.diffs, diffs)
+		})
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	expected := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj"
+	actual := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk"
+
+	assert.Equal(t, strings.Join([]string{
+		"--- expected",
+		"+++ actual",
+		"@@ -1,5 +1,5 @@",
+		" a",
+		"-b",
+		"+B",
+		" c",
+		" d",
+		" e",
+		"@@ -8,3 +8,4 @@",
+		" h",
+		" i",
+		" j",
+		"+k",
+	}, "\n"), diffText(expected, actual))
+
+	long := strings.Repeat("x\n", maxTextDiffLines+1)
+	assert.Contains(t, diffText(long, long+"y"), "first difference at line")
+}
+
+func TestDiffSchema(t *testing.T) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(`{
+		"type": "object",
+		"properties": {
+			"items": {"type": "array", "items": {"type": "string", "minLength": 3}}
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	result, err := schema.Validate(gojsonschema.NewStringLoader(`{"items":["ab", 1]}`))
+	require.NoError(t, err)
+
+	diffs := diffSchema(result.Errors())
+	if assert.Len(t, diffs, 3) {
+		assert.True(t, strings.HasPrefix(diffs[0], "(root): "))
+		assert.True(t, strings.HasPrefix(diffs[1], "/items/0: "))
+		assert.True(t, strings.HasPrefix(diffs[2], "/items/1: "))
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	lines := make([]string, maxDiffLines+5)
+
+	truncated := truncateLines(lines)
+	assert.Len(t, truncated, maxDiffLines+1)
+	assert.Equal(t, "... and 5 more", truncated[maxDiffLines])
+}
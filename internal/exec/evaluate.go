@@ -2,9 +2,12 @@ package exec
 
 import (
 	"bytes"
-	"io"
 	"net/http"
+	"slices"
+	"strings"
 
+	"github.com/oneee-playground/r2d2-tester/internal/util/jsonpointer"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
 	"github.com/pkg/errors"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -20,6 +23,17 @@ func evalStatuscode(actual, expected int) error {
 	return nil
 }
 
+func evalStatuscodeIn(actual int, allowed []uint32) error {
+	if !slices.Contains(allowed, uint32(actual)) {
+		return errors.Errorf(
+			"unmatching status code. expected one of: %v, actual: %d",
+			allowed, actual,
+		)
+	}
+
+	return nil
+}
+
 func evalHeaderAtLeast(header http.Header, expected map[string]string) error {
 	for key, val := range expected {
 		got := header.Get(key)
@@ -34,40 +48,77 @@ func evalHeaderAtLeast(header http.Header, expected map[string]string) error {
 	return nil
 }
 
-func evalBodyExact(body io.ReadCloser, expected []byte) error {
-	defer body.Close()
+func evalBodyExact(body []byte, expected []byte) error {
+	if bytes.Equal(body, expected) {
+		return nil
+	}
 
-	bodyBytes, err := io.ReadAll(body)
-	if err != nil {
-		return errors.Wrap(err, "reading body")
+	// JSON bodies are easier to read path by path,
+	// unless they differ only in formatting.
+	actualDoc, actualErr := jsonpointer.Decode(body)
+	expectedDoc, expectedErr := jsonpointer.Decode(expected)
+	if actualErr == nil && expectedErr == nil {
+		if diffs := diffJSON(expectedDoc, actualDoc, false); len(diffs) > 0 {
+			return bodyMismatch(truncateLines(diffs))
+		}
 	}
 
-	if !bytes.Equal(bodyBytes, expected) {
-		return errors.Errorf(
-			"unmatching response body. expected: %s, actual: %s",
-			expected, bodyBytes,
-		)
+	return errors.Errorf("unmatching response body:\n%s", diffText(string(expected), string(body)))
+}
+
+func bodyMismatch(diffs []string) error {
+	return errors.Errorf("unmatching response body:\n%s", strings.Join(diffs, "\n"))
+}
+
+func evalBody(body []byte, expected *work.Expected) error {
+	switch expected.BodyMatch {
+	case work.Expected_EXACT:
+		return evalBodyExact(body, expected.Body)
+	case work.Expected_JSON:
+		return evalBodyJson(body, expected.Body, expected.IgnorePaths, expected.UnorderedArrays)
 	}
 
-	return nil
+	return errors.Errorf("unknown body match: %s", expected.BodyMatch)
 }
 
-func evalBodyJsonSchema(body io.ReadCloser, schema *gojsonschema.Schema) error {
-	defer body.Close()
+// evalBodyJson compares bodies as JSON documents,
+// so key order and formatting don't matter.
+func evalBodyJson(body []byte, expected []byte, ignorePaths []string, unorderedArrays bool) error {
+	actualDoc, err := jsonpointer.Decode(body)
+	if err != nil {
+		return errors.Wrap(err, "parsing response body")
+	}
 
-	bodyBytes, err := io.ReadAll(body)
+	expectedDoc, err := jsonpointer.Decode(expected)
 	if err != nil {
-		return errors.Wrap(err, "reading body")
+		return errors.Wrap(err, "parsing expected body")
+	}
+
+	for _, path := range ignorePaths {
+		if actualDoc, err = jsonpointer.Delete(actualDoc, path); err != nil {
+			return errors.Wrap(err, "ignoring path")
+		}
+		if expectedDoc, err = jsonpointer.Delete(expectedDoc, path); err != nil {
+			return errors.Wrap(err, "ignoring path")
+		}
 	}
 
+	if diffs := diffJSON(expectedDoc, actualDoc, unorderedArrays); len(diffs) > 0 {
+		return bodyMismatch(truncateLines(diffs))
+	}
+
+	return nil
+}
+
+func evalBodyJsonSchema(body []byte, schema *gojsonschema.Schema) error {
 	if schema == nil {
-		if len(bodyBytes) > 0 {
+		if len(body) > 0 {
 			return errors.New("schema not provided, but non-empty body was given")
 		}
 		return nil
 	}
 
-	bodyLoader := gojsonschema.NewBytesLoader(bodyBytes)
+	bodyLoader := gojsonschema.NewBytesLoader(body)
 
 	result, err := schema.Validate(bodyLoader)
 	if err != nil {
@@ -75,13 +126,9 @@ func evalBodyJsonSchema(body io.ReadCloser, schema *gojsonschema.Schema) error {
 	}
 
 	if !result.Valid() {
-		errs := make([]string, len(result.Errors()))
-		for idx, err := range result.Errors() {
-			errs[idx] = err.String()
-		}
-
 		return errors.Errorf(
-			"failed to validate body with schema. errors: %v", errs,
+			"failed to validate body with schema:\n%s",
+			strings.Join(truncateLines(diffSchema(result.Errors())), "\n"),
 		)
 	}
 
@@ -2,9 +2,7 @@ package exec
 
 import (
 	"bytes"
-	"io"
 	"net/http"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,6 +19,15 @@ func TestEvalStatus(t *testing.T) {
 	})
 }
 
+func TestEvalStatusIn(t *testing.T) {
+	t.Run("allowed value", func(t *testing.T) {
+		assert.NoError(t, evalStatuscodeIn(201, []uint32{200, 201}))
+	})
+	t.Run("disallowed value", func(t *testing.T) {
+		assert.Error(t, evalStatuscodeIn(204, []uint32{200, 201}))
+	})
+}
+
 func TestEvalHeaderAtLeast(t *testing.T) {
 	testcases := []struct {
 		desc    string
@@ -74,25 +81,25 @@ func TestEvalHeaderAtLeast(t *testing.T) {
 func TestEvalBodyExact(t *testing.T) {
 	testcases := []struct {
 		desc    string
-		body    io.ReadCloser
+		body    []byte
 		expect  []byte
 		wantErr bool
 	}{
 		{
 			desc:    "exact",
-			body:    io.NopCloser(strings.NewReader("example")),
+			body:    []byte("example"),
 			expect:  []byte("example"),
 			wantErr: false,
 		},
 		{
 			desc:    "unmatch (wrong chars)",
-			body:    io.NopCloser(strings.NewReader("example")),
+			body:    []byte("example"),
 			expect:  bytes.Repeat([]byte{'v'}, len("example")),
 			wantErr: true,
 		},
 		{
 			desc:    "unmatch (wrong length)",
-			body:    io.NopCloser(strings.NewReader("example")),
+			body:    []byte("example"),
 			expect:  []byte("exam"),
 			wantErr: true,
 		},
@@ -110,6 +117,89 @@ func TestEvalBodyExact(t *testing.T) {
 	}
 }
 
+func TestEvalBodyJson(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		body        string
+		expect      string
+		ignorePaths []string
+		unordered   bool
+		wantErr     bool
+	}{
+		{
+			desc:   "different key order and formatting",
+			body:   `{"title":"foo", "id":1}`,
+			expect: "{\n  \"id\": 1,\n  \"title\": \"foo\"\n}",
+		},
+		{
+			desc:    "different value",
+			body:    `{"id":1,"title":"bar"}`,
+			expect:  `{"id":1,"title":"foo"}`,
+			wantErr: true,
+		},
+		{
+			desc:    "extra key",
+			body:    `{"id":1,"title":"foo","createdAt":"2024-07-01"}`,
+			expect:  `{"id":1,"title":"foo"}`,
+			wantErr: true,
+		},
+		{
+			desc:        "extra key ignored",
+			body:        `{"id":1,"title":"foo","createdAt":"2024-07-01"}`,
+			expect:      `{"id":1,"title":"foo"}`,
+			ignorePaths: []string{"/createdAt"},
+		},
+		{
+			desc:        "generated ids ignored",
+			body:        `[{"id":"a1","title":"foo"},{"id":"b2","title":"bar"}]`,
+			expect:      `[{"id":"x","title":"foo"},{"id":"y","title":"bar"}]`,
+			ignorePaths: []string{"/*/id"},
+		},
+		{
+			desc:    "array order matters by default",
+			body:    `[2,1]`,
+			expect:  `[1,2]`,
+			wantErr: true,
+		},
+		{
+			desc:      "unordered arrays",
+			body:      `{"tags":[2,1,1]}`,
+			expect:    `{"tags":[1,2,1]}`,
+			unordered: true,
+		},
+		{
+			desc:      "unordered arrays with different multiplicity",
+			body:      `[1,1,2]`,
+			expect:    `[1,2,2]`,
+			unordered: true,
+			wantErr:   true,
+		},
+		{
+			desc:    "malformed body",
+			body:    `{"id":`,
+			expect:  `{"id":1}`,
+			wantErr: true,
+		},
+		{
+			desc:    "trailing data",
+			body:    `{"id":1}{"oops":true} garbage`,
+			expect:  `{"id":1}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := evalBodyJson([]byte(tc.body), []byte(tc.expect), tc.ignorePaths, tc.unordered)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestEvalBodyJsonSchema(t *testing.T) {
 	schemaString := `
 {
@@ -274,14 +364,12 @@ func TestEvalBodyJsonSchema(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.desc, func(t *testing.T) {
-			bodyReader := io.NopCloser(strings.NewReader(tc.body))
-
 			s := schema
 			if tc.noSchema {
 				s = nil
 			}
 
-			err := evalBodyJsonSchema(bodyReader, s)
+			err := evalBodyJsonSchema([]byte(tc.body), s)
 			if tc.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -290,3 +378,15 @@ func TestEvalBodyJsonSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestEvalBodyDiff(t *testing.T) {
+	err := evalBodyJson([]byte(`{"id":1,"title":"bar"}`), []byte(`{"id":1,"title":"foo"}`), nil, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `/title: value changed. expected: "foo", actual: "bar"`)
+	}
+
+	err = evalBodyExact([]byte("hello\nworld"), []byte("hello\nthere"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "-there\n+world")
+	}
+}
@@ -2,13 +2,17 @@ package exec
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/event"
 	"github.com/oneee-playground/r2d2-tester/internal/job"
 	"github.com/oneee-playground/r2d2-tester/internal/metric"
+	"github.com/oneee-playground/r2d2-tester/internal/record"
+	"github.com/oneee-playground/r2d2-tester/internal/report"
 	"github.com/oneee-playground/r2d2-tester/internal/work"
 	"github.com/pkg/errors"
 	"github.com/xeipuuv/gojsonschema"
@@ -31,13 +35,25 @@ type ExecOpts struct {
 	WorkStorage   work.Storage
 	MetricStorage *metric.Storage
 	Docker        client.APIClient
+
+	// Results keeps result record of every executed work. It is optional.
+	Results *record.Writer
 }
 
 type Executor struct {
 	processes      []*process
 	primaryProcess *process
 
-	metrics *metric.WriteSession
+	metrics    *metric.WriteSession
+	latencies  []event.LatencySummary
+	thresholds []event.ThresholdResult
+
+	report  []report.Section
+	current *report.Section
+	scores  []event.SectionScore
+
+	sections      []event.SectionResult
+	currentResult *event.SectionResult
 
 	ExecOpts
 }
@@ -47,15 +63,20 @@ func NewExecutor(opts ExecOpts) *Executor {
 	return e
 }
 
-func (e *Executor) Execute(ctx context.Context, jobToExec job.Job) error {
+// Execute runs every section of the job, and returns the result of it.
+func (e *Executor) Execute(ctx context.Context, jobToExec job.Job) event.Result {
 	e.Log.Info("execution started")
 
 	start := time.Now()
 	taskID := jobToExec.TaskID
 
+	e.beginSections(jobToExec.Sections)
+
 	defer e.teardownResources(ctx)
 	if err := e.setupResources(ctx, taskID, jobToExec.Resources, jobToExec.Submission); err != nil {
-		return errors.Wrap(err, "setting up resources")
+		err = categorize(failureResource, errors.Wrap(err, "setting up resources"))
+		e.skipSections(jobToExec.Sections, err)
+		return e.result(start, newFailure(event.StageResource, err, ""))
 	}
 
 	// TODO: Add health check for containers. You can do it.
@@ -63,6 +84,8 @@ func (e *Executor) Execute(ctx context.Context, jobToExec job.Job) error {
 	// time.Sleep(5 * time.Second)
 
 	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	session, errchan := e.MetricStorage.WriteSession(taskID.String(), jobToExec.Submission.ID.String())
 	go func() {
 		if err, ok := <-errchan; ok {
@@ -75,50 +98,217 @@ func (e *Executor) Execute(ctx context.Context, jobToExec job.Job) error {
 
 	e.startMetricCollection(ctx, cancel)
 
+	// Every section runs even if the previous one failed.
+	var firstFailure *event.Failure
+
 	for idx, section := range jobToExec.Sections {
+		if ctx.Err() != nil {
+			// Remaining sections are skipped.
+			e.skipSections(jobToExec.Sections[idx:], context.Cause(ctx))
+			break
+		}
+
 		e.setTimestamp(time.Now(), section.ID, "start-exec")
 		e.Log.Info("started execution of section",
 			zap.Int("index", idx),
 			zap.String("id", section.ID.String()),
 		)
 
-		templates, err := e.fetchTemplates(ctx, taskID, section.ID)
-		if err != nil {
-			return err
+		e.beginSection(idx)
+
+		if err := e.executeSection(ctx, taskID, section); err != nil {
+			e.Log.Info("section failed", zap.Error(err))
+
+			// Failure on our side overrides others, since the verdict can't be trusted.
+			if failure := e.currentResult.Failure; firstFailure == nil || (failure.Internal && !firstFailure.Internal) {
+				firstFailure = failure
+			}
 		}
+	}
+
+	if firstFailure == nil && ctx.Err() != nil {
+		err := categorize(failureCanceled, errors.Wrap(context.Cause(ctx), "execution canceled"))
+		firstFailure = newFailure(event.StageTest, err, "")
+	}
+
+	result := e.result(start, firstFailure)
+
+	e.Log.Info("execution done",
+		zap.Duration("took", result.Took),
+		zap.Bool("success", result.Success),
+		zap.Float64("score", e.Score().Total),
+	)
+
+	return result
+}
+
+// executeSection runs the section with its hooks, and scores it.
+// It returns the first failure of the section.
+func (e *Executor) executeSection(ctx context.Context, taskID uuid.UUID, section job.Section) error {
+	sectionResult := e.currentResult
+
+	// fail closes the section that failed before the test.
+	fail := func(stage string, err error) error {
+		sectionResult.Status = event.StatusFailed
+		sectionResult.Failure = newFailure(stage, err, "")
+
+		e.finishSection(section, 0, "", err)
+		e.scoreSection(section, grade{}, err)
+		return err
+	}
+
+	setupStart := time.Now()
+	err := e.runHooks(ctx, taskID, section.ID, section.Setup)
+	sectionResult.Setup = time.Since(setupStart)
 
+	if err != nil {
+		return fail(event.StageSetup, categorize(failureHook, errors.Wrap(err, "setting up section")))
+	}
+
+	templates, err := e.fetchStreamTemplates(ctx, taskID, sectionStreams(section))
+	if err != nil {
+		return fail(event.StageSetup, storageFailure(err))
+	}
+
+	e.Log.Info("determined section type", zap.String("type", string(section.Type)))
+
+	start := time.Now()
+	e.setTimestamp(start, section.ID, "start-request")
+
+	var (
+		// output summarizes load sections in the report.
+		output string
+		g      grade
+		// firstFailed is the key of the first failed work.
+		firstFailed string
+	)
+
+	switch section.Type {
+	case job.TypeScenario:
 		stream, errchan := e.WorkStorage.Stream(ctx, taskID, section.ID)
 
-		e.Log.Info("determined section type", zap.String("type", string(section.Type)))
+		progress := newProgress(e.Log, e.countWorks(ctx, taskID, section.ID))
+
+		var stats scenarioStats
+		stats, err = e.testScenario(ctx, section, templates, stream, errchan, progress)
+
+		g.passRatio = stats.passRatio()
+
+		sectionResult.Passed, sectionResult.Failed = stats.passed, stats.total-stats.passed
+		firstFailed = stats.firstFailed
+	case job.TypeLoad:
+		// Load may stop before consuming every work.
+		streamCtx, cancelStream := context.WithCancel(ctx)
+		stream, errchan := e.streamMixed(streamCtx, taskID, section)
+
+		progress := newProgress(e.Log, e.countWorks(ctx, taskID, streamIDs(section)...))
 
-		start := time.Now()
-		e.setTimestamp(start, section.ID, "start-request")
+		var stats loadStats
+		stats, err = e.testLoad(ctx, section, templates, stream, errchan, progress)
+		cancelStream()
 
-		switch section.Type {
-		case job.TypeScenario:
-			err = e.testScenario(ctx, section.ID, templates, stream, errchan)
-		case job.TypeLoad:
-			var dueMissed int
-			dueMissed, err = e.testLoad(ctx, section.ID, section.RPM, templates, stream, errchan)
+		if stats.dueMissed > 0 {
+			e.Log.Info("test has missed dues", zap.Int("missed", stats.dueMissed))
+		}
+
+		if stats.failures > 0 {
+			e.Log.Info("test has failed works",
+				zap.Int("failures", stats.failures),
+				zap.Int("requests", stats.requests),
+				zap.String("categories", formatFailures(stats.failuresByCategory)),
+			)
+		}
 
-			if dueMissed > 0 {
-				e.Log.Info("test has missed dues", zap.Int("missed", dueMissed))
+		// Aborted load earns nothing.
+		if err == nil {
+			var breaches []event.Breach
+			breaches, err = e.checkThresholds(section, stats)
+
+			var tolerance float64
+			if section.Scoring != nil {
+				tolerance = section.Scoring.Tolerance
 			}
+			g.slo = sloScore(section.Thresholds, breaches, tolerance)
+		}
+
+		output = fmt.Sprintf("requests: %d, failures: %d, missed dues: %d",
+			stats.requests, stats.failures, stats.dueMissed)
+		if stats.failures > 0 {
+			output += fmt.Sprintf(" (%s)", formatFailures(stats.failuresByCategory))
 		}
 
-		e.setTimestamp(time.Now(), section.ID, "request-done")
+		sectionResult.Passed, sectionResult.Failed = stats.requests-stats.failures, stats.failures
+		sectionResult.Load = newLoadStats(stats)
+		firstFailed = stats.firstFailed
+	case job.TypeVirtualUser:
+		open := func(ctx context.Context, user int) (<-chan taggedWork, <-chan error) {
+			// Users don't share the order of works.
+			mix := section
+			mix.Seed += int64(user)
+			return e.streamMixed(ctx, taskID, mix)
+		}
 
-		e.Log.Info("section execution done", zap.Duration("took", time.Since(start)))
+		// Duration bounded run has no known total.
+		total := 0
+		if section.Duration == 0 || section.Iterations > 0 {
+			total = e.countWorks(ctx, taskID, streamIDs(section)...) * int(section.Users) * int(max(section.Iterations, 1))
+		}
+		progress := newProgress(e.Log, total)
 
-		if err != nil {
-			cancel(err)
-			return errors.Wrapf(err, "testing %s", section.Type)
+		var stats userStats
+		stats, err = e.testVirtualUser(ctx, section, templates, open, progress)
+
+		e.Log.Info("achieved throughput", zap.Float64("rpm", stats.throughput))
+
+		output = fmt.Sprintf("throughput: %.2f rpm", stats.throughput)
+
+		sectionResult.Passed, sectionResult.Failed = stats.completed, stats.failed
+		sectionResult.Throughput = stats.throughput
+		firstFailed = stats.firstFailed
+	}
+
+	took := time.Since(start)
+
+	e.setTimestamp(time.Now(), section.ID, "request-done")
+
+	e.Log.Info("section execution done", zap.Duration("took", took))
+
+	// Teardown runs even if the test failed.
+	teardownStart := time.Now()
+	teardownErr := e.runHooks(ctx, taskID, section.ID, section.Teardown)
+	sectionResult.Teardown = time.Since(teardownStart)
+
+	sectionResult.Took = took
+	sectionResult.Status = event.StatusPassed
+
+	if teardownErr != nil {
+		teardownErr = categorize(failureHook, errors.Wrap(teardownErr, "tearing down section"))
+	}
+
+	if err != nil {
+		err = errors.Wrapf(err, "testing %s", section.Type)
+
+		sectionResult.Status = event.StatusFailed
+		sectionResult.Failure = newFailure(event.StageTest, err, firstFailed)
+
+		// Test failure comes first, but teardown failure is kept too.
+		if teardownErr != nil {
+			e.Log.Warn("teardown failed after test failure", zap.Error(teardownErr))
+			sectionResult.TeardownFailure = newFailure(event.StageTeardown, teardownErr, "")
 		}
+	} else if teardownErr != nil {
+		err = teardownErr
+
+		sectionResult.Status = event.StatusFailed
+		sectionResult.Failure = newFailure(event.StageTeardown, err, "")
 	}
 
-	e.Log.Info("execution done", zap.Duration("took", time.Since(start)))
+	e.finishSection(section, took, output, err)
+
+	score := e.scoreSection(section, g, err)
+	e.Log.Info("section scored", zap.Float64("score", score))
 
-	return nil
+	return err
 }
 
 func (e *Executor) fetchTemplates(ctx context.Context, taskID, sectionID uuid.UUID) (map[uuid.UUID]template, error) {
@@ -141,36 +331,106 @@ func (e *Executor) fetchTemplates(ctx context.Context, taskID, sectionID uuid.UU
 }
 
 type schema struct {
-	headers    map[string]string
-	jsonSchema *gojsonschema.Schema
+	// source is kept for result records.
+	source *work.TemplatedSchema
+
+	headers        map[string]string
+	headerMatchers []headerMatcher
+	jsonSchema     *gojsonschema.Schema
+}
+
+type statusRange struct {
+	from, to int
+	schema   schema
 }
 
 type template struct {
 	schemaTable map[int]schema
+	classTable  map[int]schema
+	rangeTable  []statusRange
+}
+
+// lookup finds schema for the status code.
+// Exact codes take precedence over ranges, and ranges over classes.
+func (t template) lookup(status int) (schema, bool) {
+	if s, ok := t.schemaTable[status]; ok {
+		return s, true
+	}
+
+	for _, r := range t.rangeTable {
+		if r.from <= status && status <= r.to {
+			return r.schema, true
+		}
+	}
+
+	s, ok := t.classTable[status/100]
+	return s, ok
 }
 
 func processTemplate(workTemplate *work.Template) (template, error) {
-	t := template{schemaTable: make(map[int]schema, len(workTemplate.SchemaTable))}
+	t := template{
+		schemaTable: make(map[int]schema, len(workTemplate.SchemaTable)),
+		classTable:  make(map[int]schema, len(workTemplate.ClassTable)),
+		rangeTable:  make([]statusRange, len(workTemplate.RangeTable)),
+	}
 
 	for status, val := range workTemplate.SchemaTable {
-		var s *gojsonschema.Schema
+		s, err := processSchema(val)
+		if err != nil {
+			return template{}, errors.Wrapf(err, "processing schema for status %d", status)
+		}
 
-		if len(val.BodySchema) > 0 {
-			loader := gojsonschema.NewBytesLoader(val.BodySchema)
+		t.schemaTable[int(status)] = s
+	}
 
-			schema, err := gojsonschema.NewSchema(loader)
-			if err != nil {
-				return template{}, errors.Wrap(err, "creating schema")
-			}
+	for class, val := range workTemplate.ClassTable {
+		s, err := processSchema(val)
+		if err != nil {
+			return template{}, errors.Wrapf(err, "processing schema for class %dxx", class)
+		}
+
+		t.classTable[int(class)] = s
+	}
 
-			s = schema
+	for idx, val := range workTemplate.RangeTable {
+		if val.From > val.To {
+			return template{}, errors.Errorf("malformed status range: %d-%d", val.From, val.To)
 		}
 
-		t.schemaTable[int(status)] = schema{
-			headers:    val.Headers,
-			jsonSchema: s,
+		s, err := processSchema(val.Schema)
+		if err != nil {
+			return template{}, errors.Wrapf(err, "processing schema for range %d-%d", val.From, val.To)
 		}
+
+		t.rangeTable[idx] = statusRange{from: int(val.From), to: int(val.To), schema: s}
 	}
 
 	return t, nil
 }
+
+func processSchema(val *work.TemplatedSchema) (schema, error) {
+	var s *gojsonschema.Schema
+
+	if len(val.GetBodySchema()) > 0 {
+		loader := gojsonschema.NewBytesLoader(val.BodySchema)
+
+		jsonSchema, err := gojsonschema.NewSchema(loader)
+		if err != nil {
+			return schema{}, errors.Wrap(err, "creating schema")
+		}
+
+		s = jsonSchema
+	}
+
+	matchers, err := compileHeaderMatchers(val.GetHeaderMatchers())
+	if err != nil {
+		return schema{}, err
+	}
+
+	return schema{
+		source:         val,
+		headers:        val.GetHeaders(),
+		headerMatchers: matchers,
+		jsonSchema:     s,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package exec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/metric"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestExecutor makes executor of the section against target.
+// Metrics are written to a server discarding them.
+func newTestExecutor(t *testing.T, target *process, section job.Section) *Executor {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := influxdb2.NewClient(srv.URL, "")
+	t.Cleanup(client.Close)
+
+	session, _ := metric.NewStorage(client).WriteSession("org", "bucket")
+	t.Cleanup(session.Close)
+
+	e := NewExecutor(ExecOpts{Log: zap.NewNop(), HTTPClient: http.DefaultClient})
+	e.metrics = session
+	e.primaryProcess = target
+
+	e.beginSections([]job.Section{section})
+	e.beginSection(0)
+
+	return e
+}
+
+func TestTemplateLookup(t *testing.T) {
+	header := func(v string) *work.TemplatedSchema {
+		return &work.TemplatedSchema{Headers: map[string]string{"X-Schema": v}}
+	}
+
+	raw := &work.Template{
+		SchemaTable: map[uint32]*work.TemplatedSchema{
+			204: header("exact"),
+		},
+		RangeTable: []*work.StatusRange{
+			{From: 200, To: 209, Schema: header("range")},
+			{From: 400, To: 404, Schema: header("range-client")},
+		},
+		ClassTable: map[uint32]*work.TemplatedSchema{
+			2: header("class"),
+			4: header("class-client"),
+		},
+	}
+
+	tmpl, err := processTemplate(raw)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		status int
+		want   string
+	}{
+		{status: 204, want: "exact"},
+		{status: 201, want: "range"},
+		{status: 226, want: "class"},
+		{status: 404, want: "range-client"},
+		{status: 422, want: "class-client"},
+		{status: 500, want: ""},
+	}
+
+	for _, tc := range testcases {
+		s, ok := tmpl.lookup(tc.status)
+		if tc.want == "" {
+			assert.False(t, ok, "status %d", tc.status)
+			continue
+		}
+		if assert.True(t, ok, "status %d", tc.status) {
+			assert.Equal(t, tc.want, s.headers["X-Schema"], "status %d", tc.status)
+		}
+	}
+}
+
+func TestProcessTemplateMalformedRange(t *testing.T) {
+	_, err := processTemplate(&work.Template{
+		RangeTable: []*work.StatusRange{{From: 299, To: 200}},
+	})
+	assert.Error(t, err)
+}
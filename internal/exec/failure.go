@@ -0,0 +1,117 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+// failureCategory tells why a work failed.
+type failureCategory string
+
+const (
+	failureTimeout    failureCategory = "timeout"
+	failureConnection failureCategory = "connection"
+	failureStatus     failureCategory = "status"
+	failureHeader     failureCategory = "header"
+	failureBody       failureCategory = "body"
+
+	// Failures of a section as a whole.
+	failureBudget    failureCategory = "error-budget"
+	failureThreshold failureCategory = "threshold"
+
+	// Failures not caused by works.
+	failureHook     failureCategory = "hook"
+	failureResource failureCategory = "resource"
+	failureCanceled failureCategory = "canceled"
+
+	// Work storage failures are not the submission's fault.
+	// failureStorage is unreadable storage, e.g. missing work file.
+	// failureIntegrity is damaged work storage.
+	failureStorage   failureCategory = "storage"
+	failureIntegrity failureCategory = "storage-integrity"
+
+	// failureDefinition is a malformed work. e.g. invalid header pattern.
+	failureDefinition failureCategory = "work-definition"
+
+	failureOther failureCategory = "other"
+)
+
+// failure is an error of a work with its category.
+type failure struct {
+	category failureCategory
+	err      error
+}
+
+func (f *failure) Error() string { return f.err.Error() }
+func (f *failure) Cause() error  { return f.err }
+func (f *failure) Unwrap() error { return f.err }
+
+// categorize attaches category to err. It returns nil if err is nil.
+func categorize(category failureCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &failure{category: category, err: err}
+}
+
+// categoryOf returns category of the error.
+// Uncategorized errors fall into failureOther.
+func categoryOf(err error) failureCategory {
+	var f *failure
+	if errors.As(err, &f) {
+		return f.category
+	}
+
+	return failureOther
+}
+
+// storageFailure categorizes errors from work storage.
+func storageFailure(err error) error {
+	if errors.Is(err, work.ErrCorrupt) {
+		return categorize(failureIntegrity, err)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// Streaming stopped by us.
+		return categorize(failureCanceled, err)
+	}
+
+	return categorize(failureStorage, err)
+}
+
+// internal tells the failure is on our side, so the submission can't be judged.
+func (c failureCategory) internal() bool {
+	return c == failureStorage || c == failureIntegrity
+}
+
+// errorBudget returns the number of failed works the section tolerates.
+func errorBudget(section job.Section) int {
+	if section.ErrorBudget != nil {
+		return *section.ErrorBudget
+	}
+
+	// Error rate is judged after the run.
+	if section.Thresholds != nil && section.Thresholds.MaxErrorRate != nil {
+		return math.MaxInt
+	}
+
+	return 0
+}
+
+// formatFailures formats counts like "status: 2, timeout: 1".
+func formatFailures(counts map[failureCategory]int) string {
+	descs := make([]string, 0, len(counts))
+	for category, count := range counts {
+		descs = append(descs, fmt.Sprintf("%s: %d", category, count))
+	}
+	sort.Strings(descs)
+
+	return strings.Join(descs, ", ")
+}
@@ -0,0 +1,106 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFailureCategory(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1}`))
+	})
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	target := newTestTarget(t, mux)
+
+	newWork := func(path string, expected *work.Expected) *work.Work {
+		return &work.Work{
+			Input:         &work.Input{Method: "GET", Path: path},
+			ExpectedValue: expected,
+			Timeout:       durationpb.New(20 * time.Millisecond),
+		}
+	}
+
+	testcases := []struct {
+		desc     string
+		target   *process
+		work     *work.Work
+		expected failureCategory
+	}{
+		{
+			desc:     "status mismatch",
+			target:   target,
+			work:     newWork("/ok", &work.Expected{Status: http.StatusCreated}),
+			expected: failureStatus,
+		},
+		{
+			desc:     "body mismatch",
+			target:   target,
+			work:     newWork("/ok", &work.Expected{Status: http.StatusOK, Body: []byte(`{"id":2}`)}),
+			expected: failureBody,
+		},
+		{
+			desc:     "timeout",
+			target:   target,
+			work:     newWork("/slow", &work.Expected{Status: http.StatusOK}),
+			expected: failureTimeout,
+		},
+		{
+			desc:     "connection error",
+			target:   &process{Hostname: "127.0.0.1", Port: 1},
+			work:     newWork("/ok", &work.Expected{Status: http.StatusOK}),
+			expected: failureConnection,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			w := &worker{target: tc.target, httpClient: http.DefaultClient}
+
+			_, err := w.do(context.Background(), tc.work)
+			assert.Error(t, err)
+			assert.Equal(t, tc.expected, categoryOf(err))
+		})
+	}
+
+	t.Run("uncategorized", func(t *testing.T) {
+		assert.Equal(t, failureOther, categoryOf(errors.New("something")))
+		assert.Nil(t, categorize(failureBody, nil))
+	})
+}
+
+func TestErrorBudget(t *testing.T) {
+	budget, errorRate := 10, 0.01
+
+	assert.Equal(t, 0, errorBudget(job.Section{}))
+	assert.Equal(t, 10, errorBudget(job.Section{ErrorBudget: &budget}))
+	assert.Greater(t, errorBudget(job.Section{
+		Thresholds: &job.Thresholds{MaxErrorRate: &errorRate},
+	}), 1<<30)
+}
+
+func TestStorageFailure(t *testing.T) {
+	err := storageFailure(errors.New("opening work path"))
+	assert.Equal(t, failureStorage, categoryOf(err))
+	assert.True(t, newFailure(event.StageTest, err, "").Internal)
+
+	err = storageFailure(errors.Wrap(context.Canceled, "streaming works"))
+	assert.Equal(t, failureCanceled, categoryOf(err))
+	assert.False(t, newFailure(event.StageTest, err, "").Internal)
+
+	err = storageFailure(errors.Wrap(work.ErrCorrupt, "decoding work"))
+	assert.Equal(t, failureIntegrity, categoryOf(err))
+	assert.True(t, newFailure(event.StageTest, err, "").Internal)
+}
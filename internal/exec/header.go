@@ -0,0 +1,138 @@
+package exec
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+type headerMatcher struct {
+	*work.HeaderMatcher
+	re *regexp.Regexp
+}
+
+func (m headerMatcher) String() string {
+	switch m.Mode {
+	case work.HeaderMatcher_PRESENT, work.HeaderMatcher_ABSENT:
+		return fmt.Sprintf("%s(%s)", m.Mode, m.Key)
+	case work.HeaderMatcher_ALL_VALUES:
+		return fmt.Sprintf("%s(%s: %q)", m.Mode, m.Key, m.Values)
+	}
+
+	return fmt.Sprintf("%s(%s: %q)", m.Mode, m.Key, m.Value)
+}
+
+// maxCachedPatterns bounds patterns kept by patternCache.
+const maxCachedPatterns = 1024
+
+// patternCache keeps compiled patterns.
+// Generated works share a few patterns, and load sections do thousands of works per minute.
+type patternCache struct {
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+var headerPatterns = &patternCache{patterns: make(map[string]*regexp.Regexp)}
+
+func (c *patternCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	re, ok := c.patterns[pattern]
+	c.mu.Unlock()
+
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if len(c.patterns) < maxCachedPatterns {
+		c.patterns[pattern] = re
+	}
+	c.mu.Unlock()
+
+	return re, nil
+}
+
+func compileHeaderMatchers(matchers []*work.HeaderMatcher) ([]headerMatcher, error) {
+	compiled := make([]headerMatcher, len(matchers))
+
+	for idx, matcher := range matchers {
+		compiled[idx] = headerMatcher{HeaderMatcher: matcher}
+
+		if matcher.Mode == work.HeaderMatcher_REGEX {
+			re, err := headerPatterns.compile(matcher.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "compiling pattern for header %s", matcher.Key)
+			}
+			compiled[idx].re = re
+		}
+	}
+
+	return compiled, nil
+}
+
+func evalHeaderMatchers(header http.Header, matchers []headerMatcher) error {
+	for _, matcher := range matchers {
+		if err := matcher.match(header); err != nil {
+			return errors.Wrapf(err, "header matcher %s failed", matcher)
+		}
+	}
+
+	return nil
+}
+
+func (m headerMatcher) match(header http.Header) error {
+	values := header.Values(m.Key)
+
+	switch m.Mode {
+	case work.HeaderMatcher_PRESENT:
+		if len(values) == 0 {
+			return errors.New("header is missing")
+		}
+		return nil
+	case work.HeaderMatcher_ABSENT:
+		if len(values) > 0 {
+			return errors.Errorf("header is present. actual: %q", values)
+		}
+		return nil
+	case work.HeaderMatcher_ALL_VALUES:
+		if !slices.Equal(values, m.Values) {
+			return errors.Errorf("actual: %q", values)
+		}
+		return nil
+	}
+
+	if len(values) == 0 {
+		return errors.New("header is missing")
+	}
+
+	got := header.Get(m.Key)
+
+	var ok bool
+	switch m.Mode {
+	case work.HeaderMatcher_EXACT:
+		ok = got == m.Value
+	case work.HeaderMatcher_PREFIX:
+		ok = strings.HasPrefix(got, m.Value)
+	case work.HeaderMatcher_REGEX:
+		ok = m.re.MatchString(got)
+	default:
+		return errors.Errorf("unknown mode: %s", m.Mode)
+	}
+
+	if !ok {
+		return errors.Errorf("actual: %q", got)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalHeaderMatchers(t *testing.T) {
+	header := http.Header{
+		"Content-Type": []string{"application/json; charset=utf-8"},
+		"Set-Cookie":   []string{"session=foo; HttpOnly", "theme=dark"},
+	}
+
+	testcases := []struct {
+		desc    string
+		matcher *work.HeaderMatcher
+		wantErr bool
+	}{
+		{
+			desc:    "exact",
+			matcher: &work.HeaderMatcher{Key: "Content-Type", Mode: work.HeaderMatcher_EXACT, Value: "application/json; charset=utf-8"},
+		},
+		{
+			desc:    "exact unmatch",
+			matcher: &work.HeaderMatcher{Key: "Content-Type", Mode: work.HeaderMatcher_EXACT, Value: "application/json"},
+			wantErr: true,
+		},
+		{
+			desc:    "prefix",
+			matcher: &work.HeaderMatcher{Key: "content-type", Mode: work.HeaderMatcher_PREFIX, Value: "application/json"},
+		},
+		{
+			desc:    "regex",
+			matcher: &work.HeaderMatcher{Key: "Content-Type", Mode: work.HeaderMatcher_REGEX, Value: `^application/json(;.*)?$`},
+		},
+		{
+			desc:    "regex on missing header",
+			matcher: &work.HeaderMatcher{Key: "X-Foo", Mode: work.HeaderMatcher_REGEX, Value: `.*`},
+			wantErr: true,
+		},
+		{
+			desc:    "present",
+			matcher: &work.HeaderMatcher{Key: "Set-Cookie", Mode: work.HeaderMatcher_PRESENT},
+		},
+		{
+			desc:    "present but missing",
+			matcher: &work.HeaderMatcher{Key: "Location", Mode: work.HeaderMatcher_PRESENT},
+			wantErr: true,
+		},
+		{
+			desc:    "absent",
+			matcher: &work.HeaderMatcher{Key: "X-Powered-By", Mode: work.HeaderMatcher_ABSENT},
+		},
+		{
+			desc:    "absent but present",
+			matcher: &work.HeaderMatcher{Key: "Content-Type", Mode: work.HeaderMatcher_ABSENT},
+			wantErr: true,
+		},
+		{
+			desc:    "all values",
+			matcher: &work.HeaderMatcher{Key: "Set-Cookie", Mode: work.HeaderMatcher_ALL_VALUES, Values: []string{"session=foo; HttpOnly", "theme=dark"}},
+		},
+		{
+			desc:    "all values unmatch",
+			matcher: &work.HeaderMatcher{Key: "Set-Cookie", Mode: work.HeaderMatcher_ALL_VALUES, Values: []string{"session=foo; HttpOnly"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			matchers, err := compileHeaderMatchers([]*work.HeaderMatcher{tc.matcher})
+			require.NoError(t, err)
+
+			err = evalHeaderMatchers(header, matchers)
+			if tc.wantErr {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.matcher.Mode.String())
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCompileHeaderMatchersInvalidPattern(t *testing.T) {
+	_, err := compileHeaderMatchers([]*work.HeaderMatcher{
+		{Key: "Content-Type", Mode: work.HeaderMatcher_REGEX, Value: "("},
+	})
+	assert.Error(t, err)
+}
+
+func TestCompileHeaderMatchersCached(t *testing.T) {
+	matchers := []*work.HeaderMatcher{
+		{Key: "Content-Type", Mode: work.HeaderMatcher_REGEX, Value: "^application/json"},
+	}
+
+	first, err := compileHeaderMatchers(matchers)
+	require.NoError(t, err)
+
+	second, err := compileHeaderMatchers(matchers)
+	require.NoError(t, err)
+
+	assert.Same(t, first[0].re, second[0].re)
+}
+
+func TestInvalidPatternIsWorkDefinitionFailure(t *testing.T) {
+	w := &worker{httpClient: http.DefaultClient}
+
+	_, err := w.do(context.Background(), &work.Work{
+		Input: &work.Input{Method: "GET", Path: "/"},
+		ExpectedValue: &work.Expected{
+			HeaderMatchers: []*work.HeaderMatcher{{Key: "Content-Type", Mode: work.HeaderMatcher_REGEX, Value: "("}},
+		},
+	})
+	assert.Equal(t, failureDefinition, categoryOf(err))
+}
@@ -0,0 +1,126 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// defaultHookTimeout bounds hooks without timeout, so a hanging one doesn't block the section.
+const defaultHookTimeout = 5 * time.Minute
+
+func (e *Executor) runHooks(ctx context.Context, taskID, sectionID uuid.UUID, hooks []job.Hook) error {
+	for idx, hook := range hooks {
+		start := time.Now()
+
+		if err := e.runHook(ctx, taskID, sectionID, hook); err != nil {
+			return errors.Wrapf(err, "running hook %d on %s", idx, hook.Resource)
+		}
+
+		e.Log.Info("hook done",
+			zap.String("resource", hook.Resource),
+			zap.Strings("command", hook.Command),
+			zap.Duration("took", time.Since(start)),
+		)
+	}
+
+	return nil
+}
+
+func (e *Executor) runHook(ctx context.Context, taskID, sectionID uuid.UUID, hook job.Hook) error {
+	var proc *process
+	for _, p := range e.processes {
+		if p.Hostname == hook.Resource {
+			proc = p
+		}
+	}
+
+	if proc == nil {
+		return errors.Errorf("unknown resource: %s", hook.Resource)
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdin io.ReadCloser
+	if hook.File != "" {
+		file, err := e.WorkStorage.Fixture(ctx, taskID, sectionID, hook.File)
+		if err != nil {
+			return errors.Wrap(err, "fetching fixture")
+		}
+		defer file.Close()
+
+		stdin = file
+	}
+
+	execConf := container.ExecOptions{
+		Cmd:          hook.Command,
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+
+	created, err := e.Docker.ContainerExecCreate(ctx, proc.ID, execConf)
+	if err != nil {
+		return errors.Wrap(err, "creating exec")
+	}
+
+	attached, err := e.Docker.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return errors.Wrap(err, "attaching exec")
+	}
+	defer attached.Close()
+
+	// Reading output isn't stopped by ctx. The command is left running in the container.
+	stop := context.AfterFunc(ctx, attached.Close)
+	defer stop()
+
+	writeErr := make(chan error, 1)
+	go func() {
+		var err error
+		if stdin != nil {
+			_, err = io.Copy(attached.Conn, stdin)
+		}
+		if closeErr := attached.CloseWrite(); err == nil {
+			err = closeErr
+		}
+		writeErr <- err
+	}()
+
+	var output bytes.Buffer
+	_, err = stdcopy.StdCopy(&output, &output, attached.Reader)
+	if ctx.Err() != nil {
+		return errors.Wrapf(ctx.Err(), "waiting hook for %s. output: %s", timeout, output.String())
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading exec output")
+	}
+
+	if err := <-writeErr; err != nil {
+		return errors.Wrap(err, "writing fixture to exec")
+	}
+
+	inspect, err := e.Docker.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return errors.Wrap(err, "inspecting exec")
+	}
+
+	if inspect.ExitCode != 0 {
+		return errors.Errorf("exited with code %d. output: %s", inspect.ExitCode, output.String())
+	}
+
+	return nil
+}
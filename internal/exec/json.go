@@ -0,0 +1,105 @@
+package exec
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// stringifyJSON returns strings as is and other values in JSON form.
+func stringifyJSON(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+
+	// Values decoded from JSON are always encodable.
+	b, _ := json.Marshal(val)
+
+	return string(b)
+}
+
+// jsonType returns type name of decoded JSON value as JSON Schema names it.
+func jsonType(val any) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// jsonEqual reports whether two decoded JSON values are equal.
+// Numbers are compared by value, so 1 and 1.0 are equal.
+// If unordered is set, arrays are compared as multisets.
+func jsonEqual(a, b any, unordered bool) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, val := range x {
+			other, ok := y[key]
+			if !ok || !jsonEqual(val, other, unordered) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		if unordered {
+			return jsonEqualUnordered(x, y)
+		}
+		for idx := range x {
+			if !jsonEqual(x[idx], y[idx], false) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, errx := strconv.ParseFloat(string(x), 64)
+		fy, erry := strconv.ParseFloat(string(y), 64)
+		return errx == nil && erry == nil && fx == fy
+	default:
+		return a == b
+	}
+}
+
+func jsonEqualUnordered(x, y []any) bool {
+	used := make([]bool, len(y))
+
+outer:
+	for _, a := range x {
+		for idx, b := range y {
+			if !used[idx] && jsonEqual(a, b, true) {
+				used[idx] = true
+				continue outer
+			}
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,138 @@
+package exec
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/influxdata/influxdb-client-go/api/write"
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/metric"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+)
+
+const (
+	scopeSection = "section"
+	scopeStream  = "stream"
+)
+
+const (
+	// kindResponse is measured from when the request was intended to be sent.
+	// It includes queueing delay, and is the one used for grading.
+	kindResponse = "response"
+	// kindService is measured from when the request was actually sent.
+	kindService = "service"
+)
+
+// latencyRecorder keeps histograms of the whole section,
+// and of each stream inside it if scoped by stream.
+// Latency of each work is left to results, since a histogram per work is too heavy.
+type latencyRecorder struct {
+	scope string
+	kind  string
+	total *metric.Histogram
+
+	mu     sync.Mutex
+	groups map[string]*metric.Histogram
+}
+
+func newLatencyRecorder(scope, kind string) *latencyRecorder {
+	return &latencyRecorder{
+		scope:  scope,
+		kind:   kind,
+		total:  metric.NewHistogram(),
+		groups: make(map[string]*metric.Histogram),
+	}
+}
+
+func (r *latencyRecorder) record(key string, d time.Duration) {
+	r.recordCorrected(key, d, 0, 0)
+}
+
+// recordCorrected records latency with ones of the requests skipped after it.
+func (r *latencyRecorder) recordCorrected(key string, d, interval time.Duration, skipped int) {
+	r.total.RecordCorrected(d, interval, skipped)
+
+	if r.scope == scopeSection {
+		return
+	}
+
+	r.mu.Lock()
+	h, ok := r.groups[key]
+	if !ok {
+		h = metric.NewHistogram()
+		r.groups[key] = h
+	}
+	r.mu.Unlock()
+
+	h.RecordCorrected(d, interval, skipped)
+}
+
+// summaries returns summary of the section first, and then of groups sorted by key.
+func (r *latencyRecorder) summaries(sectionID uuid.UUID) []event.LatencySummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summaries := []event.LatencySummary{{
+		SectionID: sectionID,
+		Scope:     scopeSection,
+		Kind:      r.kind,
+		Summary:   r.total.Summary(),
+	}}
+
+	keys := make([]string, 0, len(r.groups))
+	for key := range r.groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		summaries = append(summaries, event.LatencySummary{
+			SectionID: sectionID,
+			Scope:     r.scope,
+			Kind:      r.kind,
+			Key:       key,
+			Summary:   r.groups[key].Summary(),
+		})
+	}
+
+	return summaries
+}
+
+// writeLatencySummaries writes summaries to metric session,
+// and keeps them to be published.
+func (e *Executor) writeLatencySummaries(sectionID uuid.UUID, r *latencyRecorder) {
+	now := time.Now()
+
+	for _, s := range r.summaries(sectionID) {
+		tags := map[string]string{
+			"section-id": sectionID.String(),
+			"scope":      s.Scope,
+			"kind":       s.Kind,
+		}
+		if s.Key != "" {
+			tags["key"] = s.Key
+		}
+
+		e.metrics.Write(write.NewPoint("latency-summary", tags, s.Summary.Fields(), now))
+
+		e.latencies = append(e.latencies, s)
+	}
+}
+
+// workKey returns printable id of the work.
+func workKey(w *work.Work) string {
+	id, err := uuid.FromBytes(w.Id)
+	if err != nil {
+		return hex.EncodeToString(w.Id)
+	}
+
+	return id.String()
+}
+
+// LatencySummaries returns latency summaries of executed sections.
+func (e *Executor) LatencySummaries() []event.LatencySummary {
+	return e.latencies
+}
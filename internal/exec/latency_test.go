@@ -0,0 +1,61 @@
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyRecorderSummaries(t *testing.T) {
+	r := newLatencyRecorder(scopeStream, kindResponse)
+
+	for i := 0; i < 10; i++ {
+		r.record("b", 10*time.Millisecond)
+		r.record("a", 20*time.Millisecond)
+	}
+
+	sectionID := uuid.New()
+	summaries := r.summaries(sectionID)
+
+	if !assert.Len(t, summaries, 3) {
+		return
+	}
+
+	assert.Equal(t, scopeSection, summaries[0].Scope)
+	assert.Equal(t, "", summaries[0].Key)
+	assert.Equal(t, int64(20), summaries[0].Summary.Count)
+
+	assert.Equal(t, scopeStream, summaries[1].Scope)
+	assert.Equal(t, "a", summaries[1].Key)
+	assert.Equal(t, int64(10), summaries[1].Summary.Count)
+	assert.InEpsilon(t, float64(20*time.Millisecond), float64(summaries[1].Summary.P50), 0.001)
+
+	assert.Equal(t, "b", summaries[2].Key)
+
+	for _, s := range summaries {
+		assert.Equal(t, sectionID, s.SectionID)
+		assert.Equal(t, kindResponse, s.Kind)
+	}
+}
+
+func TestWorkKey(t *testing.T) {
+	id := uuid.New()
+	assert.Equal(t, id.String(), workKey(&work.Work{Id: id[:]}))
+	assert.Equal(t, "0102", workKey(&work.Work{Id: []byte{1, 2}}))
+}
+
+func TestLatencyRecorderSectionScope(t *testing.T) {
+	r := newLatencyRecorder(scopeSection, kindResponse)
+
+	r.record("a", 10*time.Millisecond)
+	r.record("b", 20*time.Millisecond)
+
+	summaries := r.summaries(uuid.New())
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, scopeSection, summaries[0].Scope)
+		assert.Equal(t, int64(2), summaries[0].Summary.Count)
+	}
+}
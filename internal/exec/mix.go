@@ -0,0 +1,149 @@
+package exec
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+// taggedWork is a work with the stream it came from.
+type taggedWork struct {
+	work   *work.Work
+	stream uuid.UUID
+}
+
+type weightedSource struct {
+	id      uuid.UUID
+	weight  uint64
+	stream  <-chan *work.Work
+	errchan <-chan error
+}
+
+// sectionStreams returns streams to mix for the section.
+func sectionStreams(section job.Section) []job.Stream {
+	if len(section.Streams) == 0 {
+		return []job.Stream{{ID: section.ID, Weight: 1}}
+	}
+
+	return section.Streams
+}
+
+func streamIDs(section job.Section) []uuid.UUID {
+	streams := sectionStreams(section)
+
+	ids := make([]uuid.UUID, len(streams))
+	for idx, s := range streams {
+		ids[idx] = s.ID
+	}
+
+	return ids
+}
+
+func (e *Executor) fetchStreamTemplates(ctx context.Context, taskID uuid.UUID, streams []job.Stream) (map[uuid.UUID]template, error) {
+	merged := make(map[uuid.UUID]template)
+
+	for _, s := range streams {
+		templates, err := e.fetchTemplates(ctx, taskID, s.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "stream %s", s.ID)
+		}
+
+		for id, t := range templates {
+			merged[id] = t
+		}
+	}
+
+	return merged, nil
+}
+
+func (e *Executor) streamMixed(ctx context.Context, taskID uuid.UUID, section job.Section) (<-chan taggedWork, <-chan error) {
+	streams := sectionStreams(section)
+
+	sources := make([]weightedSource, len(streams))
+	for idx, s := range streams {
+		workStream, errchan := e.WorkStorage.Stream(ctx, taskID, s.ID)
+
+		sources[idx] = weightedSource{
+			id:      s.ID,
+			weight:  s.Weight,
+			stream:  workStream,
+			errchan: errchan,
+		}
+	}
+
+	return mixStreams(ctx, section.Seed, sources)
+}
+
+// mixStreams picks works from sources randomly by their weights.
+// Exhausted sources are dropped from the mix.
+// Source's error is expected to be sent before its stream is closed.
+func mixStreams(ctx context.Context, seed int64, sources []weightedSource) (<-chan taggedWork, <-chan error) {
+	out := make(chan taggedWork)
+	errchan := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+
+		rng := rand.New(rand.NewPCG(uint64(seed), 0))
+
+		sources := append([]weightedSource(nil), sources...)
+		for len(sources) > 0 {
+			idx := pickWeighted(rng, sources)
+
+			var w *work.Work
+			var ok bool
+
+			select {
+			case <-ctx.Done():
+				return
+			case w, ok = <-sources[idx].stream:
+			}
+
+			if !ok {
+				select {
+				case err := <-sources[idx].errchan:
+					errchan <- errors.Wrapf(err, "stream %s", sources[idx].id)
+					return
+				default:
+				}
+
+				sources = append(sources[:idx], sources[idx+1:]...)
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- taggedWork{work: w, stream: sources[idx].id}:
+			}
+		}
+	}()
+
+	return out, errchan
+}
+
+func pickWeighted(rng *rand.Rand, sources []weightedSource) int {
+	var total uint64
+	for _, s := range sources {
+		total += s.weight
+	}
+
+	if total == 0 {
+		// Every weight is zero. Pick uniformly.
+		return rng.IntN(len(sources))
+	}
+
+	n := rng.Uint64N(total)
+	for idx, s := range sources {
+		if n < s.weight {
+			return idx
+		}
+		n -= s.weight
+	}
+
+	return len(sources) - 1
+}
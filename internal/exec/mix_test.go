@@ -0,0 +1,91 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func newTestSource(weight uint64, cnt int, err error) weightedSource {
+	stream := make(chan *work.Work, cnt)
+	errchan := make(chan error, 1)
+
+	for i := 0; i < cnt; i++ {
+		stream <- &work.Work{}
+	}
+	if err != nil {
+		errchan <- err
+	}
+	close(stream)
+
+	return weightedSource{id: uuid.New(), weight: weight, stream: stream, errchan: errchan}
+}
+
+func collectMixed(t *testing.T, seed int64, sources []weightedSource) ([]uuid.UUID, error) {
+	stream, errchan := mixStreams(context.Background(), seed, sources)
+
+	var got []uuid.UUID
+	for w := range stream {
+		got = append(got, w.stream)
+	}
+
+	select {
+	case err := <-errchan:
+		return got, err
+	default:
+		return got, nil
+	}
+}
+
+func TestMixStreams(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sources := []weightedSource{
+		newTestSource(70, 1000, nil),
+		newTestSource(20, 1000, nil),
+		newTestSource(10, 1000, nil),
+	}
+	ids := []uuid.UUID{sources[0].id, sources[1].id, sources[2].id}
+
+	got, err := collectMixed(t, 42, sources)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3000)
+
+	// Proportion of the first 1000 works follows the weights.
+	counts := make(map[uuid.UUID]int)
+	for _, id := range got[:1000] {
+		counts[id]++
+	}
+	assert.InDelta(t, 700, counts[ids[0]], 60)
+	assert.InDelta(t, 200, counts[ids[1]], 60)
+	assert.InDelta(t, 100, counts[ids[2]], 60)
+
+	t.Run("deterministic with seed", func(t *testing.T) {
+		mix := func() []int {
+			sources := []weightedSource{newTestSource(1, 50, nil), newTestSource(3, 50, nil)}
+			got, _ := collectMixed(t, 7, sources)
+
+			picks := make([]int, len(got))
+			for idx, id := range got {
+				if id == sources[1].id {
+					picks[idx] = 1
+				}
+			}
+			return picks
+		}
+
+		assert.Equal(t, mix(), mix())
+	})
+
+	t.Run("source error", func(t *testing.T) {
+		sources := []weightedSource{newTestSource(1, 0, errors.New("decoding work"))}
+
+		_, err := collectMixed(t, 0, sources)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package exec
+
+import (
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/report"
+)
+
+// beginSections lists every section as skipped until it is executed.
+func (e *Executor) beginSections(sections []job.Section) {
+	e.report = make([]report.Section, len(sections))
+	e.sections = make([]event.SectionResult, len(sections))
+
+	for idx, section := range sections {
+		e.report[idx] = report.Section{
+			ID:      section.ID,
+			Type:    string(section.Type),
+			Skipped: true,
+		}
+		e.sections[idx] = event.SectionResult{
+			ID:     section.ID,
+			Type:   string(section.Type),
+			Status: event.StatusSkipped,
+		}
+	}
+}
+
+// beginSection marks the section at idx as executed.
+// Cases and results reported afterwards belong to it.
+func (e *Executor) beginSection(idx int) {
+	e.current = &e.report[idx]
+	e.current.Skipped = false
+
+	e.currentResult = &e.sections[idx]
+}
+
+// result makes result of the job. The job succeeded if there was no failure.
+func (e *Executor) result(start time.Time, failure *event.Failure) event.Result {
+	return event.Result{
+		Version:  event.ResultVersion,
+		Success:  failure == nil,
+		Took:     time.Since(start),
+		Failure:  failure,
+		Sections: e.sections,
+	}
+}
+
+func newFailure(stage string, err error, workID string) *event.Failure {
+	category := categoryOf(err)
+
+	return &event.Failure{
+		Stage:    stage,
+		Category: string(category),
+		Message:  err.Error(),
+		WorkID:   workID,
+		Internal: category.internal(),
+	}
+}
+
+func newLoadStats(stats loadStats) *event.LoadStats {
+	byCategory := make(map[string]int, len(stats.failuresByCategory))
+	for category, count := range stats.failuresByCategory {
+		byCategory[string(category)] = count
+	}
+
+	return &event.LoadStats{
+		Requests:           stats.requests,
+		Failures:           stats.failures,
+		MissedDues:         stats.dueMissed,
+		RPM:                stats.rpm(),
+		ErrorRate:          stats.errorRate(),
+		FailuresByCategory: byCategory,
+	}
+}
@@ -0,0 +1,64 @@
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	sections := []job.Section{
+		{ID: uuid.New(), Type: job.TypeScenario},
+		{ID: uuid.New(), Type: job.TypeLoad},
+	}
+
+	e := new(Executor)
+	e.beginSections(sections)
+	e.beginSection(0)
+
+	err := errors.Wrap(categorize(failureStatus, errors.New("unmatching status code")), "testing SCENARIO")
+	failure := newFailure(event.StageTest, err, "work-1")
+
+	e.currentResult.Status = event.StatusFailed
+	e.currentResult.Failure = failure
+
+	result := e.result(time.Now(), failure)
+	assert.Equal(t, event.ResultVersion, result.Version)
+	assert.False(t, result.Success)
+	assert.Equal(t, &event.Failure{
+		Stage:    event.StageTest,
+		Category: string(failureStatus),
+		Message:  "testing SCENARIO: unmatching status code",
+		WorkID:   "work-1",
+	}, result.Failure)
+
+	if assert.Len(t, result.Sections, 2) {
+		assert.Equal(t, event.StatusFailed, result.Sections[0].Status)
+		assert.Equal(t, event.StatusSkipped, result.Sections[1].Status)
+		assert.Equal(t, sections[1].ID, result.Sections[1].ID)
+	}
+}
+
+func TestNewLoadStats(t *testing.T) {
+	stats := newLoadStats(loadStats{
+		requests:           100,
+		failures:           4,
+		dueMissed:          2,
+		elapsed:            30 * time.Second,
+		failuresByCategory: map[failureCategory]int{failureTimeout: 3, failureBody: 1},
+	})
+
+	assert.Equal(t, &event.LoadStats{
+		Requests:           100,
+		Failures:           4,
+		MissedDues:         2,
+		RPM:                200,
+		ErrorRate:          0.04,
+		FailuresByCategory: map[string]int{"timeout": 3, "body": 1},
+	}, stats)
+}
@@ -0,0 +1,77 @@
+package exec
+
+import (
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+)
+
+// defaultRPM is the flat load of section without RPM.
+const defaultRPM = 60
+
+// profileRecheck bounds how long the load waits before reading the profile again.
+// Without it, a due computed from a low rate would skip over the following stages.
+const profileRecheck = time.Second
+
+// loadProfile tells offered load at the given time of the test.
+type loadProfile struct {
+	flatRPM uint64
+	stages  []job.Stage
+}
+
+func newLoadProfile(section job.Section) loadProfile {
+	flatRPM := section.RPM
+	if flatRPM == 0 {
+		flatRPM = defaultRPM
+	}
+
+	return loadProfile{flatRPM: flatRPM, stages: section.Profile}
+}
+
+// at returns offered RPM and the index of the stage at elapsed time.
+// It reports done if every stage has passed.
+func (p loadProfile) at(elapsed time.Duration) (rpm float64, stage int, done bool) {
+	if len(p.stages) == 0 {
+		return float64(p.flatRPM), 0, false
+	}
+
+	var prevRPM float64
+	for idx, s := range p.stages {
+		target := float64(s.TargetRPM)
+
+		if elapsed < s.Duration {
+			if s.Transition == job.TransitionLinear {
+				progress := float64(elapsed) / float64(s.Duration)
+				return prevRPM + (target-prevRPM)*progress, idx, false
+			}
+			return target, idx, false
+		}
+
+		elapsed -= s.Duration
+		prevRPM = target
+	}
+
+	return prevRPM, len(p.stages) - 1, true
+}
+
+// requestInterval converts RPM into the interval between requests.
+// It returns zero for zero RPM, which means idle.
+func requestInterval(rpm float64) time.Duration {
+	if rpm <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(time.Minute) / rpm)
+}
+
+// untilDue returns how long to wait from now for the due after lastDue.
+// It waits at most profileRecheck, so the rate is read again by then.
+// Non-positive duration means it is due.
+func untilDue(interval time.Duration, lastDue, now time.Time) time.Duration {
+	if interval == 0 {
+		// Idle until the rate rises.
+		return profileRecheck
+	}
+
+	return min(lastDue.Add(interval).Sub(now), profileRecheck)
+}
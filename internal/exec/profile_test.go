@@ -0,0 +1,77 @@
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProfile(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		rpm, _, _ := newLoadProfile(job.Section{}).at(time.Minute)
+		assert.Equal(t, float64(defaultRPM), rpm)
+	})
+
+	t.Run("flat", func(t *testing.T) {
+		p := newLoadProfile(job.Section{RPM: 600})
+
+		rpm, stage, done := p.at(time.Hour)
+		assert.Equal(t, 600.0, rpm)
+		assert.Equal(t, 0, stage)
+		assert.False(t, done)
+	})
+
+	// Ramp up to 600 RPM, hold it, spike to 6000 RPM, and ramp down.
+	p := newLoadProfile(job.Section{
+		Profile: []job.Stage{
+			{Duration: time.Minute, TargetRPM: 600, Transition: job.TransitionLinear},
+			{Duration: time.Minute, TargetRPM: 600, Transition: job.TransitionInstant},
+			{Duration: 10 * time.Second, TargetRPM: 6000, Transition: job.TransitionInstant},
+			{Duration: time.Minute, TargetRPM: 0, Transition: job.TransitionLinear},
+		},
+	})
+
+	testcases := []struct {
+		elapsed time.Duration
+		rpm     float64
+		stage   int
+		done    bool
+	}{
+		{elapsed: 0, rpm: 0, stage: 0},
+		{elapsed: 30 * time.Second, rpm: 300, stage: 0},
+		{elapsed: 90 * time.Second, rpm: 600, stage: 1},
+		{elapsed: 125 * time.Second, rpm: 6000, stage: 2},
+		{elapsed: 160 * time.Second, rpm: 3000, stage: 3},
+		{elapsed: 200 * time.Second, rpm: 0, stage: 3, done: true},
+	}
+
+	for _, tc := range testcases {
+		rpm, stage, done := p.at(tc.elapsed)
+		assert.InDelta(t, tc.rpm, rpm, 0.001, "elapsed %s", tc.elapsed)
+		assert.Equal(t, tc.stage, stage, "elapsed %s", tc.elapsed)
+		assert.Equal(t, tc.done, done, "elapsed %s", tc.elapsed)
+	}
+}
+
+func TestRequestInterval(t *testing.T) {
+	assert.Equal(t, time.Duration(0), requestInterval(0))
+	assert.Equal(t, 2*time.Second, requestInterval(30))
+	assert.Equal(t, time.Second, requestInterval(60))
+	assert.Equal(t, 100*time.Millisecond, requestInterval(600))
+}
+
+func TestUntilDue(t *testing.T) {
+	now := time.Now()
+
+	// Idle.
+	assert.Equal(t, profileRecheck, untilDue(0, now, now))
+
+	// Low rate is followed by rechecking.
+	assert.Equal(t, profileRecheck, untilDue(requestInterval(6), now, now))
+	assert.Equal(t, time.Second, untilDue(requestInterval(6), now.Add(-9*time.Second), now))
+	assert.LessOrEqual(t, untilDue(requestInterval(6), now.Add(-10*time.Second), now), time.Duration(0))
+
+	assert.Equal(t, 100*time.Millisecond, untilDue(requestInterval(600), now, now))
+}
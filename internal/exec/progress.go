@@ -0,0 +1,69 @@
+package exec
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// progressUnknownStep is the logging interval when total is unknown.
+const progressUnknownStep = 100
+
+// progress logs how many works are done in a section.
+// It is safe for concurrent use.
+type progress struct {
+	log  *zap.Logger
+	step int
+
+	mu    sync.Mutex
+	done  int
+	total int
+}
+
+// countWorks returns the number of works in the streams.
+// It returns 0 if storage can't count any of them.
+func (e *Executor) countWorks(ctx context.Context, taskID uuid.UUID, streamIDs ...uuid.UUID) int {
+	total := 0
+	for _, id := range streamIDs {
+		n, err := e.WorkStorage.Count(ctx, taskID, id)
+		if err != nil {
+			e.Log.Debug("counting works failed", zap.Error(err))
+			return 0
+		}
+		total += n
+	}
+
+	return total
+}
+
+// newProgress makes progress of total works.
+// Progress is logged without total if it is 0.
+func newProgress(log *zap.Logger, total int) *progress {
+	step := progressUnknownStep
+	if total > 0 {
+		// Roughly every 10%.
+		step = max(total/10, 1)
+	}
+
+	return &progress{log: log, total: total, step: step}
+}
+
+func (p *progress) advance() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+
+	if p.done%p.step != 0 && p.done != p.total {
+		return
+	}
+
+	if p.total > 0 {
+		p.log.Info("work progress", zap.Int("done", p.done), zap.Int("total", p.total))
+		return
+	}
+
+	p.log.Info("work progress", zap.Int("done", p.done))
+}
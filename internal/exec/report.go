@@ -0,0 +1,74 @@
+package exec
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/report"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+)
+
+// reportWork reports a work of scenario section as a case.
+func (e *Executor) reportWork(w *work.Work, res result, err error) {
+	name := fmt.Sprintf("%s %s", w.GetInput().GetMethod(), w.GetInput().GetPath())
+	if len(w.Id) > 0 {
+		name = fmt.Sprintf("%s (%s)", name, workKey(w))
+	}
+
+	e.current.Cases = append(e.current.Cases, report.Case{
+		Name:    name,
+		Took:    res.took,
+		Failure: reportFailure(err),
+	})
+}
+
+// finishSection closes the current section.
+// Load sections are reported as a single aggregated case with the output.
+// Error not attributed to any case is reported as a case of the section itself.
+func (e *Executor) finishSection(section job.Section, took time.Duration, output string, err error) {
+	e.current.Took = took
+
+	switch section.Type {
+	case job.TypeLoad, job.TypeVirtualUser:
+		e.current.Cases = append(e.current.Cases, report.Case{
+			Name:    "load",
+			Took:    took,
+			Failure: reportFailure(err),
+			Output:  output,
+		})
+		return
+	}
+
+	if err == nil {
+		return
+	}
+
+	for _, c := range e.current.Cases {
+		if c.Failure != nil {
+			return
+		}
+	}
+
+	e.current.Cases = append(e.current.Cases, report.Case{
+		Name:    "section",
+		Took:    took,
+		Failure: reportFailure(err),
+	})
+}
+
+func reportFailure(err error) *report.Failure {
+	if err == nil {
+		return nil
+	}
+
+	return &report.Failure{
+		Message:  err.Error(),
+		Category: string(categoryOf(err)),
+	}
+}
+
+// ReportSections returns report of every section in the job.
+func (e *Executor) ReportSections() []report.Section {
+	return e.report
+}
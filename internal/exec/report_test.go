@@ -0,0 +1,55 @@
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportSections(t *testing.T) {
+	sections := []job.Section{
+		{ID: uuid.New(), Type: job.TypeScenario},
+		{ID: uuid.New(), Type: job.TypeLoad},
+		{ID: uuid.New(), Type: job.TypeScenario},
+	}
+
+	e := new(Executor)
+	e.beginSections(sections)
+
+	// Scenario passes every work, but fails on teardown.
+	e.beginSection(0)
+	e.reportWork(&work.Work{Input: &work.Input{Method: "GET", Path: "/boards"}}, result{took: time.Millisecond}, nil)
+	e.finishSection(sections[0], time.Second, "", errors.New("tearing down section"))
+
+	// Load is aggregated into a case.
+	e.beginSection(1)
+	e.finishSection(sections[1], time.Minute, "requests: 10", categorize(failureStatus, errors.New("budget exhausted")))
+
+	report := e.ReportSections()
+	if !assert.Len(t, report, 3) {
+		return
+	}
+
+	scenario := report[0]
+	assert.False(t, scenario.Skipped)
+	assert.Equal(t, time.Second, scenario.Took)
+	if assert.Len(t, scenario.Cases, 2) {
+		assert.Equal(t, "GET /boards", scenario.Cases[0].Name)
+		assert.Nil(t, scenario.Cases[0].Failure)
+		assert.Equal(t, "section", scenario.Cases[1].Name)
+		assert.Equal(t, "tearing down section", scenario.Cases[1].Failure.Message)
+	}
+
+	load := report[1]
+	if assert.Len(t, load.Cases, 1) {
+		assert.Equal(t, "requests: 10", load.Cases[0].Output)
+		assert.Equal(t, string(failureStatus), load.Cases[0].Failure.Category)
+	}
+
+	assert.True(t, report[2].Skipped)
+}
@@ -0,0 +1,72 @@
+package exec
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// maxResultBodySize bounds response body kept in a result record.
+const maxResultBodySize = 1024
+
+// newResult makes result record of the work.
+// Request, response and expected value are kept only if the work failed.
+// For templated works, expected value is the schema for the received status.
+func newResult(sectionID uuid.UUID, w *work.Work, res result, err error) *work.Result {
+	r := &work.Result{
+		WorkId:     w.Id,
+		SectionId:  sectionID[:],
+		Passed:     err == nil,
+		Status:     uint32(res.trace.status),
+		Latency:    durationpb.New(res.took),
+		Attempts:   uint32(res.attempts),
+		Assertions: res.trace.assertions,
+	}
+
+	if err == nil {
+		return r
+	}
+
+	r.Failure = string(categoryOf(err))
+	r.Error = err.Error()
+	r.Request = res.trace.request
+	r.Expected = w.ExpectedValue
+	r.ExpectedSchema = res.trace.schema
+
+	// Nothing was received if the request didn't reach the target.
+	if res.trace.status != 0 {
+		body, truncated := res.trace.body, false
+		if len(body) > maxResultBodySize {
+			body, truncated = body[:maxResultBodySize], true
+		}
+
+		headers := make(map[string]string, len(res.trace.header))
+		for key, vals := range res.trace.header {
+			headers[key] = strings.Join(vals, ", ")
+		}
+
+		r.Response = &work.Response{
+			Status:    uint32(res.trace.status),
+			Headers:   headers,
+			Body:      body,
+			Truncated: truncated,
+		}
+	}
+
+	return r
+}
+
+// writeResult writes result record of the work if results are kept.
+// Failing to write it doesn't fail the test.
+func (e *Executor) writeResult(sectionID uuid.UUID, w *work.Work, res result, err error) {
+	if e.Results == nil {
+		return
+	}
+
+	if err := e.Results.Write(newResult(sectionID, w, res, err)); err != nil {
+		e.Log.Warn("failed to write result", zap.Error(err))
+	}
+}
@@ -0,0 +1,112 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestNewResult(t *testing.T) {
+	body := `{"items":"` + strings.Repeat("a", 2*maxResultBodySize) + `"}`
+
+	target := newTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+
+	sectionID := uuid.New()
+	workID := uuid.New()
+
+	newWork := func(assertion *work.Assertion) *work.Work {
+		return &work.Work{
+			Id:    workID[:],
+			Input: &work.Input{Method: "GET", Path: "/items"},
+			ExpectedValue: &work.Expected{
+				Status:     http.StatusOK,
+				Assertions: []*work.Assertion{assertion},
+			},
+			Timeout: durationpb.New(time.Second),
+		}
+	}
+
+	w := &worker{target: target, httpClient: http.DefaultClient}
+
+	t.Run("passed", func(t *testing.T) {
+		passing := newWork(&work.Assertion{Path: "/items", Operator: work.Assertion_TYPE_OF, Value: []byte("string")})
+
+		res, err := w.do(context.Background(), passing)
+		assert.NoError(t, err)
+
+		r := newResult(sectionID, passing, res, err)
+		assert.True(t, r.Passed)
+		assert.Equal(t, workID[:], r.WorkId)
+		assert.Equal(t, sectionID[:], r.SectionId)
+		assert.Equal(t, uint32(http.StatusOK), r.Status)
+		assert.Equal(t, uint32(1), r.Attempts)
+		if assert.Len(t, r.Assertions, 1) {
+			assert.True(t, r.Assertions[0].Passed)
+		}
+
+		assert.Nil(t, r.Request)
+		assert.Nil(t, r.Response)
+		assert.Nil(t, r.Expected)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		failing := newWork(&work.Assertion{Path: "/items", Operator: work.Assertion_TYPE_OF, Value: []byte("array")})
+
+		res, err := w.do(context.Background(), failing)
+		assert.Error(t, err)
+
+		r := newResult(sectionID, failing, res, err)
+		assert.False(t, r.Passed)
+		assert.Equal(t, string(failureBody), r.Failure)
+		assert.NotEmpty(t, r.Error)
+		if assert.Len(t, r.Assertions, 1) {
+			assert.False(t, r.Assertions[0].Passed)
+			assert.Contains(t, r.Assertions[0].Message, "expected type: array")
+		}
+
+		assert.Equal(t, "/items", r.Request.GetPath())
+		assert.Equal(t, failing.ExpectedValue, r.Expected)
+
+		if assert.NotNil(t, r.Response) {
+			assert.True(t, r.Response.Truncated)
+			assert.Equal(t, body[:maxResultBodySize], string(r.Response.Body))
+			assert.Equal(t, "application/json", r.Response.Headers["Content-Type"])
+		}
+	})
+	t.Run("templated", func(t *testing.T) {
+		templateID := uuid.New()
+		source := &work.TemplatedSchema{BodySchema: []byte(`{"type":"array"}`)}
+
+		tmpl, err := processTemplate(&work.Template{
+			Id:          templateID[:],
+			SchemaTable: map[uint32]*work.TemplatedSchema{http.StatusOK: source},
+		})
+		assert.NoError(t, err)
+
+		templated := &work.Work{
+			Id:         workID[:],
+			Input:      &work.Input{Method: "GET", Path: "/items"},
+			TemplateId: templateID[:],
+			Timeout:    durationpb.New(time.Second),
+		}
+
+		w := &worker{target: target, httpClient: http.DefaultClient, templates: map[uuid.UUID]template{templateID: tmpl}}
+
+		res, err := w.do(context.Background(), templated)
+		assert.Error(t, err)
+
+		r := newResult(sectionID, templated, res, err)
+		assert.Nil(t, r.Expected)
+		assert.Equal(t, source, r.ExpectedSchema)
+	})
+}
@@ -0,0 +1,135 @@
+package exec
+
+import (
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+)
+
+// grade is what a section earned, before the scoring rule is applied.
+type grade struct {
+	// passRatio is the fraction of passing works in SCENARIO section.
+	passRatio float64
+	// slo is the threshold curve score in LOAD section.
+	slo float64
+}
+
+func scoringRule(section job.Section) job.ScoringRule {
+	if section.Scoring != nil && section.Scoring.Rule != "" {
+		return section.Scoring.Rule
+	}
+
+	switch section.Type {
+	case job.TypeScenario:
+		return job.ScoringPassRatio
+	case job.TypeLoad:
+		return job.ScoringSLOCurve
+	}
+
+	return job.ScoringAllOrNothing
+}
+
+// scoreSection applies scoring rule of the section, and keeps the score.
+// Rules not applicable to the section type fall back to all-or-nothing.
+func (e *Executor) scoreSection(section job.Section, g grade, err error) float64 {
+	rule := scoringRule(section)
+
+	score := 0.0
+	switch {
+	case rule == job.ScoringPassRatio && section.Type == job.TypeScenario:
+		score = g.passRatio
+	case rule == job.ScoringSLOCurve && section.Type == job.TypeLoad:
+		score = g.slo
+	default:
+		rule = job.ScoringAllOrNothing
+		if err == nil {
+			score = 1
+		}
+	}
+
+	weight := section.Weight
+	if weight == 0 {
+		weight = 1
+	}
+
+	e.scores = append(e.scores, event.SectionScore{
+		SectionID: section.ID,
+		Rule:      string(rule),
+		Weight:    weight,
+		Score:     score,
+	})
+
+	return score
+}
+
+// skipSections scores sections which didn't run. They score zero.
+func (e *Executor) skipSections(sections []job.Section, err error) {
+	for _, section := range sections {
+		e.scoreSection(section, grade{}, err)
+	}
+}
+
+// sloScore grades each threshold from 0 to 1, and averages them.
+// Met threshold scores 1. Breached one drops linearly to 0
+// until it is off by tolerance relative to its limit.
+func sloScore(t *job.Thresholds, breaches []event.Breach, tolerance float64) float64 {
+	count := countThresholds(t)
+	if count == 0 {
+		return 1
+	}
+
+	penalty := 0.0
+	for _, b := range breaches {
+		penalty += breachPenalty(b, tolerance)
+	}
+
+	return (float64(count) - penalty) / float64(count)
+}
+
+func countThresholds(t *job.Thresholds) int {
+	if t == nil {
+		return 0
+	}
+
+	count := len(t.Latency)
+	if t.MaxErrorRate != nil {
+		count++
+	}
+	if t.MinRPM > 0 {
+		count++
+	}
+	if t.MaxMissedDues != nil {
+		count++
+	}
+
+	return count
+}
+
+func breachPenalty(b event.Breach, tolerance float64) float64 {
+	if tolerance <= 0 || b.Limit <= 0 {
+		return 1
+	}
+
+	// Throughput is the only lower bound.
+	off := (b.Actual - b.Limit) / b.Limit
+	if b.Threshold == "rpm" {
+		off = (b.Limit - b.Actual) / b.Limit
+	}
+
+	return min(max(off/tolerance, 0), 1)
+}
+
+// Score returns total score of the job.
+// It is weighted average of section scores. Skipped sections score zero.
+func (e *Executor) Score() event.Score {
+	var total, weights float64
+	for _, s := range e.scores {
+		total += s.Score * s.Weight
+		weights += s.Weight
+	}
+
+	if weights > 0 {
+		total /= weights
+	}
+
+	return event.Score{Total: total, Sections: e.scores}
+}
@@ -0,0 +1,96 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLOScore(t *testing.T) {
+	errorRate := 0.01
+	thresholds := &job.Thresholds{
+		MaxErrorRate: &errorRate,
+		Latency:      []job.LatencyThreshold{{Percentile: 95, Max: 200}},
+		MinRPM:       600,
+	}
+
+	assert.Equal(t, 1.0, sloScore(nil, nil, 0))
+	assert.Equal(t, 1.0, sloScore(thresholds, nil, 0.5))
+
+	breaches := []event.Breach{
+		// 25% over the limit, which is half of the tolerance.
+		{Threshold: "p95", Limit: 200, Actual: 250},
+		// 60% under the limit. It is past the tolerance.
+		{Threshold: "rpm", Limit: 600, Actual: 240},
+	}
+
+	assert.InDelta(t, (3-0.5-1)/3.0, sloScore(thresholds, breaches, 0.5), 1e-9)
+
+	// Any breach scores zero without tolerance.
+	assert.InDelta(t, 1/3.0, sloScore(thresholds, breaches, 0), 1e-9)
+}
+
+func TestScore(t *testing.T) {
+	e := new(Executor)
+
+	scenario := job.Section{ID: uuid.New(), Type: job.TypeScenario, Weight: 2}
+	load := job.Section{ID: uuid.New(), Type: job.TypeLoad}
+	users := job.Section{ID: uuid.New(), Type: job.TypeVirtualUser}
+	strict := job.Section{
+		ID: uuid.New(), Type: job.TypeScenario,
+		Scoring: &job.Scoring{Rule: job.ScoringAllOrNothing},
+	}
+
+	failed := errors.New("failed")
+
+	assert.Equal(t, 0.75, e.scoreSection(scenario, grade{passRatio: 0.75}, failed))
+	assert.Equal(t, 0.5, e.scoreSection(load, grade{slo: 0.5}, failed))
+	assert.Equal(t, 1.0, e.scoreSection(users, grade{}, nil))
+	assert.Equal(t, 0.0, e.scoreSection(strict, grade{passRatio: 0.9}, failed))
+
+	score := e.Score()
+	assert.InDelta(t, (0.75*2+0.5+1+0)/5, score.Total, 1e-9)
+
+	if assert.Len(t, score.Sections, 4) {
+		assert.Equal(t, string(job.ScoringPassRatio), score.Sections[0].Rule)
+		assert.Equal(t, 2.0, score.Sections[0].Weight)
+		assert.Equal(t, string(job.ScoringSLOCurve), score.Sections[1].Rule)
+		assert.Equal(t, 1.0, score.Sections[1].Weight)
+		assert.Equal(t, string(job.ScoringAllOrNothing), score.Sections[2].Rule)
+	}
+}
+
+func TestScenarioPassRatio(t *testing.T) {
+	assert.Equal(t, 1.0, scenarioStats{finished: true}.passRatio())
+	assert.Equal(t, 0.5, scenarioStats{passed: 1, total: 2, finished: true}.passRatio())
+
+	// Works not done count as failed.
+	assert.Equal(t, 0.25, scenarioStats{passed: 1, total: 1, works: 4}.passRatio())
+	assert.Equal(t, 0.0, scenarioStats{passed: 1, total: 1}.passRatio())
+}
+
+func TestSkipSections(t *testing.T) {
+	e := new(Executor)
+
+	sections := []job.Section{
+		{ID: uuid.New(), Type: job.TypeScenario, Weight: 2},
+		{ID: uuid.New(), Type: job.TypeLoad},
+		{ID: uuid.New(), Type: job.TypeVirtualUser},
+	}
+	e.skipSections(sections, errors.New("setting up resources"))
+
+	score := e.Score()
+	assert.Equal(t, 0.0, score.Total)
+
+	if assert.Len(t, score.Sections, 3) {
+		for idx, s := range score.Sections {
+			assert.Equal(t, sections[idx].ID, s.SectionID)
+			assert.Equal(t, 0.0, s.Score)
+		}
+		assert.Equal(t, 2.0, score.Sections[0].Weight)
+	}
+}
@@ -2,26 +2,88 @@ package exec
 
 import (
 	"context"
+	"net/http"
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/influxdata/influxdb-client-go/api/write"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
 	"github.com/oneee-playground/r2d2-tester/internal/work"
 	"github.com/pkg/errors"
 )
 
+// scenarioStats is the outcome of SCENARIO section.
+type scenarioStats struct {
+	passed int
+	total  int
+	// works is the number of works in the section. It is 0 if unknown.
+	works int
+	// finished tells every work was done.
+	finished bool
+	// firstFailed is the key of the first failed work.
+	firstFailed string
+}
+
+// passRatio returns the fraction of passing works.
+// Section without any work is considered passed.
+// If the scenario stopped early, works not done count as failed.
+// Nothing passes then if the number of works is unknown.
+func (s scenarioStats) passRatio() float64 {
+	if !s.finished {
+		if s.works == 0 {
+			return 0
+		}
+		return float64(s.passed) / float64(max(s.works, s.total))
+	}
+
+	if s.total == 0 {
+		return 1
+	}
+	return float64(s.passed) / float64(s.total)
+}
+
+// testScenario does works in order.
+// Failed works don't stop the scenario. The first failure is returned after every work is done.
 func (e *Executor) testScenario(
-	ctx context.Context, sectionID uuid.UUID,
-	templates map[uuid.UUID]template, stream <-chan *work.Work, errchan <-chan error,
-) error {
+	ctx context.Context, section job.Section,
+	templates map[uuid.UUID]template, stream <-chan *work.Work, errchan <-chan error, progress *progress,
+) (scenarioStats, error) {
 	var work *work.Work
 	var ok bool
 
+	var (
+		stats    = scenarioStats{works: progress.total}
+		firstErr error
+	)
+
+	latencies := newLatencyRecorder(scopeSection, kindResponse)
+	defer e.writeLatencySummaries(section.ID, latencies)
+
+	httpClient := e.HTTPClient
+	if section.Session {
+		// Cookie jar lives only until the section ends.
+		client, err := newSessionClient(e.HTTPClient)
+		if err != nil {
+			return stats, err
+		}
+
+		httpClient = client
+	}
+
+	auth, err := e.authenticate(ctx, section, httpClient)
+	if err != nil {
+		return stats, err
+	}
+
+	// Variables captured from responses are only visible inside the section.
 	worker := &worker{
 		target:     e.primaryProcess,
 		templates:  templates,
-		httpClient: e.HTTPClient,
+		vars:       make(variables),
+		auth:       auth,
+		httpClient: httpClient,
 	}
 
 	defer e.metrics.Flush()
@@ -29,52 +91,103 @@ func (e *Executor) testScenario(
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return stats, ctx.Err()
 		case err := <-errchan:
-			return errors.Wrap(err, "error received from storage")
+			return stats, storageFailure(errors.Wrap(err, "error received from storage"))
 		case work, ok = <-stream:
 			if !ok {
-				return nil
+				// Error is sent before the stream is closed.
+				select {
+				case err := <-errchan:
+					return stats, storageFailure(errors.Wrap(err, "error received from storage"))
+				default:
+				}
+
+				stats.finished = true
+				if firstErr != nil {
+					return stats, errors.Wrapf(firstErr, "%d of %d works failed. first failure", stats.total-stats.passed, stats.total)
+				}
+				return stats, nil
 			}
 		}
 
-		start := time.Now()
-		if err := worker.do(ctx, work); err != nil {
-			return errors.Wrap(err, "doing work")
+		res, err := worker.do(ctx, work)
+		e.writeResult(section.ID, work, res, err)
+		e.reportWork(work, res, err)
+
+		stats.total++
+		progress.advance()
+
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return stats, err
+			}
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "doing work (attempts: %d)", res.attempts)
+				stats.firstFailed = workKey(work)
+			}
+			continue
 		}
 
-		end := time.Now()
+		stats.passed++
+
+		latencies.record("", res.took)
+
 		e.metrics.Write(write.NewPoint("response",
 			map[string]string{
-				"section-id": sectionID.String(),
+				"section-id": section.ID.String(),
 			},
 			map[string]interface{}{
-				"latency": end.Sub(start).Nanoseconds(),
+				"latency":       res.took.Nanoseconds(),
+				"first-latency": res.first.Nanoseconds(),
+				"attempts":      res.attempts,
 			},
-			end,
+			time.Now(),
 		))
 	}
 }
 
 func (e *Executor) testLoad(
-	ctx context.Context, sectionID uuid.UUID, rpm uint64,
-	templates map[uuid.UUID]template, workStream <-chan *work.Work, storageErrchan <-chan error,
-) (int, error) {
+	ctx context.Context, section job.Section,
+	templates map[uuid.UUID]template, workStream <-chan taggedWork, storageErrchan <-chan error, progress *progress,
+) (loadStats, error) {
 	defer e.metrics.Flush()
 
+	auth, err := e.authenticate(ctx, section, e.HTTPClient)
+	if err != nil {
+		return loadStats{}, err
+	}
+
+	budget := errorBudget(section)
+
 	workerPool := newWorkerPool(
-		runtime.GOMAXPROCS(0), e.primaryProcess, templates, e.HTTPClient,
+		runtime.GOMAXPROCS(0), e.primaryProcess, templates, e.HTTPClient, auth,
 	)
 
-	var requestRate time.Duration
-	if rpm <= 60 {
-		// We simply set the request rate to 1 second.
-		requestRate = time.Second
-	} else {
-		requestRate = time.Minute / time.Duration(rpm)
+	// Corrected for coordinated omission.
+	// Requests delayed by missed dues are timed from their due,
+	// and requests skipped meanwhile are recorded as well.
+	latencies := newLatencyRecorder(scopeStream, kindResponse)
+	defer e.writeLatencySummaries(section.ID, latencies)
+
+	serviceLatencies := newLatencyRecorder(scopeStream, kindService)
+	defer e.writeLatencySummaries(section.ID, serviceLatencies)
+
+	profile := newLoadProfile(section)
+	loadStart := time.Now()
+
+	// requestRate follows the profile over time.
+	requestRate := func() (time.Duration, int, bool) {
+		rpm, stage, done := profile.at(time.Since(loadStart))
+		return requestInterval(rpm), stage, done
 	}
 
-	timer := time.NewTimer(requestRate)
+	// lastDue is when the latest request was due.
+	lastDue := loadStart
+
+	initialRate, _, _ := requestRate()
+
+	timer := time.NewTimer(untilDue(initialRate, lastDue, loadStart))
 	defer timer.Stop()
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -87,9 +200,7 @@ func (e *Executor) testLoad(
 		case <-ctx.Done():
 			err = ctx.Err()
 		case e := <-storageErrchan:
-			err = errors.Wrap(e, "error received from storage")
-		case e := <-workerPool.errchan:
-			err = errors.Wrap(e, "error received from worker")
+			err = storageFailure(errors.Wrap(e, "error received from storage"))
 		}
 
 		if err != nil {
@@ -99,19 +210,51 @@ func (e *Executor) testLoad(
 
 	var (
 		worker      *concurrentWorker
-		pendingWork *work.Work
+		pendingWork *taggedWork
 		latestMiss  time.Time
+		// firstMiss is the due the pending work should have been sent at.
+		firstMiss time.Time
+		// missedBefore is the number of missed dues when firstMiss was set.
+		missedBefore int
 	)
 
 	donechan := workerPool.doneStream
 	stream := workStream
 
 	workerStart := make([]time.Time, len(workerPool.workers))
+	workerIntended := make([]time.Time, len(workerPool.workers))
+	// workerSkipped is the number of dues skipped while the work was pending,
+	// and workerInterval is the interval between them.
+	workerSkipped := make([]int, len(workerPool.workers))
+	workerInterval := make([]time.Duration, len(workerPool.workers))
+
+	stats := loadStats{
+		latency:            latencies.total,
+		failuresByCategory: make(map[failureCategory]int),
+	}
+	workerWork := make([]*work.Work, len(workerPool.workers))
+	workerStream := make([]uuid.UUID, len(workerPool.workers))
+	workerStage := make([]int, len(workerPool.workers))
 
 	feedWorker := func() {
-		worker.inputStream <- pendingWork
+		rate, stage, _ := requestRate()
+
+		worker.inputStream <- pendingWork.work
 
-		workerStart[worker.index] = time.Now()
+		now := time.Now()
+
+		intended, skipped := now, 0
+		if !firstMiss.IsZero() {
+			intended, skipped = firstMiss, stats.dueMissed-missedBefore
+		}
+
+		workerStart[worker.index] = now
+		workerIntended[worker.index] = intended
+		workerSkipped[worker.index] = skipped
+		workerInterval[worker.index] = rate
+		workerWork[worker.index] = pendingWork.work
+		workerStream[worker.index] = pendingWork.stream
+		workerStage[worker.index] = stage
 
 		// Reset current worker and work.
 		worker, pendingWork = nil, nil
@@ -121,43 +264,98 @@ func (e *Executor) testLoad(
 		stream = workStream
 
 		if latestMiss.IsZero() {
-			timer.Reset(requestRate)
+			timer.Reset(untilDue(rate, lastDue, now))
 		} else {
 			if !timer.Stop() {
 				<-timer.C
 			}
 
-			remaining := requestRate - time.Since(latestMiss)
+			remaining := rate - time.Since(latestMiss)
 			timer.Reset(min(remaining, 10*time.Microsecond))
 		}
 
-		latestMiss = time.Time{}
+		latestMiss, firstMiss = time.Time{}, time.Time{}
 	}
 
-	writeLatency := func(idx int) {
-		if start := workerStart[idx]; !start.IsZero() {
-			end := time.Now()
-
-			e.metrics.Write(write.NewPoint("response",
-				map[string]string{
-					"section-id": sectionID.String(),
-				},
-				map[string]interface{}{
-					"latency": end.Sub(start).Nanoseconds(),
-				},
-				end,
-			))
+	// collectResult returns error if the error budget is exhausted.
+	collectResult := func(idx int) error {
+		start := workerStart[idx]
+		if start.IsZero() {
+			return nil
+		}
+
+		end := time.Now()
+		last, lastErr := workerPool.workers[idx].last, workerPool.workers[idx].lastErr
+
+		tags := map[string]string{
+			"section-id": section.ID.String(),
+			"stream":     workerStream[idx].String(),
+			"stage":      strconv.Itoa(workerStage[idx]),
+		}
+
+		e.writeResult(section.ID, workerWork[idx], last, lastErr)
+
+		stats.requests++
+		progress.advance()
+		if lastErr != nil {
+			category := categoryOf(lastErr)
+
+			if stats.failures == 0 {
+				stats.firstFailed = workKey(workerWork[idx])
+			}
+
+			stats.failures++
+			stats.failuresByCategory[category]++
+			tags["failure"] = string(category)
+		}
+
+		service := end.Sub(start)
+		response := end.Sub(workerIntended[idx])
+
+		latencies.recordCorrected(workerStream[idx].String(), response, workerInterval[idx], workerSkipped[idx])
+		serviceLatencies.record(workerStream[idx].String(), service)
+
+		e.metrics.Write(write.NewPoint("response",
+			tags,
+			map[string]interface{}{
+				"latency":       service.Nanoseconds(),
+				"response-time": response.Nanoseconds(),
+				"first-latency": last.first.Nanoseconds(),
+				"attempts":      last.attempts,
+			},
+			end,
+		))
+
+		if stats.failures > budget {
+			return categorize(failureBudget, errors.Wrapf(lastErr, "error budget exhausted (%d failures: %s)",
+				stats.failures, formatFailures(stats.failuresByCategory)))
 		}
-	}
 
-	dueMissed := 0
+		return nil
+	}
 
 	for {
 		select {
 		case err := <-errchan:
 			workerPool.close()
-			return dueMissed, err
+			stats.elapsed = time.Since(loadStart)
+			return stats, err
 		case t := <-timer.C:
+			rate, _, done := requestRate()
+			if !done {
+				if wait := untilDue(rate, lastDue, t); wait > 0 {
+					// Not due yet. Rate might have changed.
+					timer.Reset(wait)
+					continue
+				}
+				lastDue = t
+			}
+
+			if done {
+				// Profile is over. Stop feeding works.
+				stream, pendingWork = nil, nil
+			}
+
 			if pendingWork != nil && worker != nil {
 				// Best case. Everything worked normally.
 				feedWorker()
@@ -166,23 +364,42 @@ func (e *Executor) testLoad(
 
 			if donechan == nil && stream == nil {
 				workerPool.close()
+
 				// Drain the channel and write metrics.
+				var err error
 				for idx := range workerPool.doneStream {
-					writeLatency(idx)
+					if collectErr := collectResult(idx); collectErr != nil && err == nil {
+						err = collectErr
+					}
 				}
-				return dueMissed, nil
+
+				stats.elapsed = time.Since(loadStart)
+				return stats, err
+			}
+
+			if done {
+				// Wait for the worker to finish.
+				timer.Reset(profileRecheck)
+				continue
 			}
 
 			if !latestMiss.IsZero() {
-				dueMissed++
+				stats.dueMissed++
 			}
 
 			latestMiss = t
-			timer.Reset(requestRate)
+			if firstMiss.IsZero() {
+				firstMiss, missedBefore = t, stats.dueMissed
+			}
+			timer.Reset(untilDue(rate, lastDue, t))
 		case workerIdx := <-donechan:
 			worker = workerPool.workers[workerIdx]
 
-			writeLatency(worker.index)
+			if err := collectResult(worker.index); err != nil {
+				workerPool.close()
+				stats.elapsed = time.Since(loadStart)
+				return stats, err
+			}
 
 			if !latestMiss.IsZero() && pendingWork != nil {
 				// Getting free worker was slower.
@@ -200,7 +417,7 @@ func (e *Executor) testLoad(
 				continue
 			}
 
-			pendingWork = work
+			pendingWork = &work
 
 			if !latestMiss.IsZero() && worker != nil {
 				// Receiving work was slower.
@@ -213,3 +430,18 @@ func (e *Executor) testLoad(
 		}
 	}
 }
+
+// authenticate logs in if the section requires auth.
+// It returns nil authenticator otherwise.
+func (e *Executor) authenticate(ctx context.Context, section job.Section, httpClient *http.Client) (*authenticator, error) {
+	if section.Auth == nil {
+		return nil, nil
+	}
+
+	auth := newAuthenticator(section.Auth, e.primaryProcess, httpClient)
+	if err := auth.login(ctx); err != nil {
+		return nil, errors.Wrap(err, "logging in")
+	}
+
+	return auth, nil
+}
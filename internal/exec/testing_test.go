@@ -0,0 +1,96 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newTestWork() *work.Work {
+	id := uuid.New()
+	return &work.Work{
+		Id:            id[:],
+		Input:         &work.Input{Method: "GET", Path: "/"},
+		ExpectedValue: &work.Expected{Status: http.StatusOK},
+		Timeout:       durationpb.New(time.Second),
+	}
+}
+
+func TestScenarioStorageError(t *testing.T) {
+	target := newTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	section := job.Section{ID: uuid.New(), Type: job.TypeScenario}
+
+	for i := 0; i < 50; i++ {
+		e := newTestExecutor(t, target, section)
+
+		// Storage sends its error and closes the stream right away.
+		stream := make(chan *work.Work, 1)
+		errchan := make(chan error, 1)
+		stream <- newTestWork()
+		errchan <- errors.Wrap(work.ErrCorrupt, "decoding work")
+		close(stream)
+
+		stats, err := e.testScenario(context.Background(), section, nil, stream, errchan, newProgress(zap.NewNop(), 0))
+		if !assert.Error(t, err) {
+			return
+		}
+		assert.Equal(t, failureIntegrity, categoryOf(err))
+		assert.False(t, stats.finished)
+	}
+}
+
+func TestLoadLatencyCorrection(t *testing.T) {
+	// Workers of the pool run concurrently.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	target := newTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(time.Second)
+		}
+	}))
+
+	section := job.Section{ID: uuid.New(), Type: job.TypeLoad, RPM: 600}
+	e := newTestExecutor(t, target, section)
+
+	stream := make(chan taggedWork)
+	go func() {
+		defer close(stream)
+
+		// The first work misses its due, and then takes long.
+		// Other workers serve the dues meanwhile.
+		time.Sleep(250 * time.Millisecond)
+
+		slow := newTestWork()
+		slow.Input.Path = "/slow"
+		slow.Timeout = durationpb.New(5 * time.Second)
+		stream <- taggedWork{work: slow, stream: section.ID}
+
+		// Load outlasts the slow work, so it isn't canceled at the end.
+		for i := 0; i < 15; i++ {
+			stream <- taggedWork{work: newTestWork(), stream: section.ID}
+		}
+	}()
+
+	stats, err := e.testLoad(context.Background(), section, nil, stream, make(chan error), newProgress(zap.NewNop(), 0))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 16, stats.requests)
+
+	// Only the dues skipped while the slow work was pending are corrected.
+	count := int(stats.latency.Summary().Count)
+	assert.GreaterOrEqual(t, count, stats.requests)
+	assert.LessOrEqual(t, count, stats.requests+stats.dueMissed)
+}
@@ -0,0 +1,147 @@
+package exec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/api/write"
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/metric"
+	"github.com/pkg/errors"
+)
+
+// loadStats is the outcome of LOAD section.
+type loadStats struct {
+	requests  int
+	failures  int
+	dueMissed int
+
+	failuresByCategory map[failureCategory]int
+	// firstFailed is the key of the first failed work.
+	firstFailed string
+	elapsed     time.Duration
+
+	// latency is response time corrected for coordinated omission.
+	latency *metric.Histogram
+}
+
+func (s loadStats) errorRate() float64 {
+	if s.requests == 0 {
+		return 0
+	}
+	return float64(s.failures) / float64(s.requests)
+}
+
+func (s loadStats) rpm() float64 {
+	if s.elapsed <= 0 {
+		return 0
+	}
+	return float64(s.requests) / s.elapsed.Minutes()
+}
+
+// evalThresholds returns every threshold the stats breached.
+func evalThresholds(t *job.Thresholds, stats loadStats) []event.Breach {
+	var breaches []event.Breach
+
+	if t.MaxErrorRate != nil {
+		if rate := stats.errorRate(); rate > *t.MaxErrorRate {
+			breaches = append(breaches, event.Breach{
+				Threshold: "error-rate",
+				Limit:     *t.MaxErrorRate,
+				Actual:    rate,
+			})
+		}
+	}
+
+	// Latency is not given if the load didn't start.
+	if stats.latency != nil {
+		for _, l := range t.Latency {
+			if actual := stats.latency.ValueAt(l.Percentile); actual >= l.Max {
+				breaches = append(breaches, event.Breach{
+					Threshold: "p" + strconv.FormatFloat(l.Percentile, 'f', -1, 64),
+					Limit:     float64(l.Max.Nanoseconds()),
+					Actual:    float64(actual.Nanoseconds()),
+				})
+			}
+		}
+	}
+
+	if t.MinRPM > 0 {
+		if rpm := stats.rpm(); rpm < t.MinRPM {
+			breaches = append(breaches, event.Breach{
+				Threshold: "rpm",
+				Limit:     t.MinRPM,
+				Actual:    rpm,
+			})
+		}
+	}
+
+	if t.MaxMissedDues != nil && stats.dueMissed > *t.MaxMissedDues {
+		breaches = append(breaches, event.Breach{
+			Threshold: "missed-dues",
+			Limit:     float64(*t.MaxMissedDues),
+			Actual:    float64(stats.dueMissed),
+		})
+	}
+
+	return breaches
+}
+
+// checkThresholds evaluates the section against its thresholds and keeps the result.
+// It returns breached thresholds, and error if any.
+func (e *Executor) checkThresholds(section job.Section, stats loadStats) ([]event.Breach, error) {
+	if section.Thresholds == nil {
+		return nil, nil
+	}
+
+	breaches := evalThresholds(section.Thresholds, stats)
+
+	result := event.ThresholdResult{
+		SectionID: section.ID,
+		Passed:    len(breaches) == 0,
+		Breaches:  breaches,
+	}
+	e.thresholds = append(e.thresholds, result)
+
+	for _, b := range breaches {
+		e.metrics.Write(write.NewPoint("threshold-breach",
+			map[string]string{
+				"section-id": section.ID.String(),
+				"threshold":  b.Threshold,
+			},
+			map[string]interface{}{
+				"limit":  b.Limit,
+				"actual": b.Actual,
+			},
+			time.Now(),
+		))
+	}
+
+	if result.Passed {
+		return nil, nil
+	}
+
+	descs := make([]string, len(breaches))
+	for idx, b := range breaches {
+		descs[idx] = describeBreach(b)
+	}
+
+	return breaches, categorize(failureThreshold, errors.Errorf("thresholds breached: %s", strings.Join(descs, ", ")))
+}
+
+func describeBreach(b event.Breach) string {
+	if strings.HasPrefix(b.Threshold, "p") {
+		limit, actual := time.Duration(b.Limit), time.Duration(b.Actual)
+		return fmt.Sprintf("%s (limit: %s, actual: %s)", b.Threshold, limit, actual)
+	}
+
+	return fmt.Sprintf("%s (limit: %v, actual: %v)", b.Threshold, b.Limit, b.Actual)
+}
+
+// ThresholdResults returns outcomes of sections with thresholds.
+func (e *Executor) ThresholdResults() []event.ThresholdResult {
+	return e.thresholds
+}
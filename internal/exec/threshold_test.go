@@ -0,0 +1,65 @@
+package exec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/event"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalThresholds(t *testing.T) {
+	latency := metric.NewHistogram()
+	for i := 1; i <= 100; i++ {
+		latency.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	// 100 requests in 30 seconds, 5 of them failed.
+	stats := loadStats{
+		requests:  100,
+		failures:  5,
+		dueMissed: 3,
+		elapsed:   30 * time.Second,
+		latency:   latency,
+	}
+
+	errorRate, missedDues := 0.1, 3
+
+	t.Run("passed", func(t *testing.T) {
+		breaches := evalThresholds(&job.Thresholds{
+			MaxErrorRate:  &errorRate,
+			Latency:       []job.LatencyThreshold{{Percentile: 95, Max: 200 * time.Millisecond}},
+			MinRPM:        200,
+			MaxMissedDues: &missedDues,
+		}, stats)
+
+		assert.Empty(t, breaches)
+	})
+
+	t.Run("breached", func(t *testing.T) {
+		errorRate, missedDues := 0.01, 0
+
+		breaches := evalThresholds(&job.Thresholds{
+			MaxErrorRate: &errorRate,
+			Latency: []job.LatencyThreshold{
+				{Percentile: 50, Max: time.Second},
+				{Percentile: 99, Max: 50 * time.Millisecond},
+			},
+			MinRPM:        300,
+			MaxMissedDues: &missedDues,
+		}, stats)
+
+		assert.Equal(t, []event.Breach{
+			{Threshold: "error-rate", Limit: 0.01, Actual: 0.05},
+			{Threshold: "p99", Limit: float64(50 * time.Millisecond), Actual: float64(latency.ValueAt(99))},
+			{Threshold: "rpm", Limit: 300, Actual: 200},
+			{Threshold: "missed-dues", Limit: 0, Actual: 3},
+		}, breaches)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		assert.Empty(t, evalThresholds(&job.Thresholds{}, stats))
+	})
+}
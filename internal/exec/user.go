@@ -0,0 +1,270 @@
+package exec
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/influxdata/influxdb-client-go/api/write"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+// userStats is the outcome of VIRTUAL_USER section.
+type userStats struct {
+	completed int
+	failed    int
+	// throughput is achieved RPM.
+	throughput float64
+	// firstFailed is the key of the first failed work.
+	firstFailed string
+}
+
+// userFailures collects failed works of users.
+type userFailures struct {
+	mu    sync.Mutex
+	count int
+	first string
+}
+
+func (f *userFailures) add(w *work.Work) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.count == 0 {
+		f.first = workKey(w)
+	}
+	f.count++
+}
+
+// openUserStream opens works for a pass of the user.
+type openUserStream func(ctx context.Context, user int) (<-chan taggedWork, <-chan error)
+
+// testVirtualUser runs closed model load test.
+// Each user loops over the works with think time between them,
+// for the iterations or until the duration ends. Without both, it does one pass.
+func (e *Executor) testVirtualUser(
+	ctx context.Context, section job.Section,
+	templates map[uuid.UUID]template, open openUserStream, progress *progress,
+) (userStats, error) {
+	defer e.metrics.Flush()
+
+	if section.Users == 0 {
+		return userStats{}, errors.New("no virtual users")
+	}
+
+	auth, err := e.authenticate(ctx, section, e.HTTPClient)
+	if err != nil {
+		return userStats{}, err
+	}
+
+	latencies := newLatencyRecorder(scopeStream, kindResponse)
+	defer e.writeLatencySummaries(section.ID, latencies)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		wg        sync.WaitGroup
+		completed atomic.Int64
+		failures  userFailures
+	)
+
+	workers := make([]*worker, section.Users)
+	for i := range workers {
+		httpClient := e.HTTPClient
+		if section.Session {
+			// Each user has its own session.
+			client, err := newSessionClient(e.HTTPClient)
+			if err != nil {
+				return userStats{}, err
+			}
+
+			httpClient = client
+		}
+
+		workers[i] = &worker{
+			target:     e.primaryProcess,
+			templates:  templates,
+			auth:       auth,
+			httpClient: httpClient,
+		}
+	}
+
+	// Users stop taking works once the run ends. Works in flight are finished.
+	end := make(chan struct{})
+	if section.Duration > 0 {
+		timer := time.AfterFunc(section.Duration, func() { close(end) })
+		defer timer.Stop()
+	}
+
+	start := time.Now()
+
+	for i, worker := range workers {
+		wg.Add(1)
+		go func(user int) {
+			defer wg.Done()
+
+			run := virtualUser{
+				id:        user,
+				worker:    worker,
+				open:      open,
+				end:       end,
+				latencies: latencies,
+				completed: &completed,
+				failures:  &failures,
+				progress:  progress,
+			}
+			if err := e.runVirtualUser(ctx, section, run); err != nil {
+				cancel(err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	throughput := float64(completed.Load()) / elapsed.Minutes()
+
+	e.metrics.Write(write.NewPoint("throughput",
+		map[string]string{
+			"section-id": section.ID.String(),
+		},
+		map[string]interface{}{
+			"rpm":       throughput,
+			"completed": completed.Load(),
+			"users":     int64(section.Users),
+		},
+		time.Now(),
+	))
+
+	stats := userStats{
+		completed:   int(completed.Load()),
+		failed:      failures.count,
+		throughput:  throughput,
+		firstFailed: failures.first,
+	}
+
+	if err := context.Cause(ctx); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+type virtualUser struct {
+	id        int
+	worker    *worker
+	open      openUserStream
+	end       <-chan struct{}
+	latencies *latencyRecorder
+	completed *atomic.Int64
+	failures  *userFailures
+	progress  *progress
+}
+
+func (e *Executor) runVirtualUser(ctx context.Context, section job.Section, user virtualUser) error {
+	iterations := section.Iterations
+	if iterations == 0 && section.Duration == 0 {
+		iterations = 1
+	}
+
+	for pass := uint64(0); iterations == 0 || pass < iterations; pass++ {
+		done, err := e.runUserPass(ctx, section, user)
+		if err != nil {
+			return err
+		}
+		if done == 0 || ctx.Err() != nil || isClosed(user.end) {
+			// Nothing more to do. Empty works would loop forever.
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// runUserPass does works of a stream once. It returns the number of works done.
+func (e *Executor) runUserPass(ctx context.Context, section job.Section, user virtualUser) (int, error) {
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
+
+	workStream, errchan := user.open(streamCtx, user.id)
+
+	done := 0
+	for {
+		var tagged taggedWork
+		var ok bool
+
+		select {
+		case <-ctx.Done():
+			return done, nil
+		case <-user.end:
+			return done, nil
+		case tagged, ok = <-workStream:
+		}
+
+		if !ok {
+			// Error is sent before the stream is closed.
+			select {
+			case err := <-errchan:
+				return done, storageFailure(errors.Wrap(err, "error received from storage"))
+			default:
+				return done, nil
+			}
+		}
+
+		res, err := user.worker.do(ctx, tagged.work)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return done, nil
+			}
+			e.writeResult(section.ID, tagged.work, res, err)
+			user.failures.add(tagged.work)
+			return done, errors.Wrapf(err, "user %d doing work", user.id)
+		}
+
+		e.writeResult(section.ID, tagged.work, res, nil)
+
+		done++
+		user.completed.Add(1)
+		user.progress.advance()
+		user.latencies.record(tagged.stream.String(), res.took)
+
+		e.metrics.Write(write.NewPoint("response",
+			map[string]string{
+				"section-id": section.ID.String(),
+				"stream":     tagged.stream.String(),
+				"user":       strconv.Itoa(user.id),
+			},
+			map[string]interface{}{
+				"latency":       res.took.Nanoseconds(),
+				"first-latency": res.first.Nanoseconds(),
+				"attempts":      res.attempts,
+			},
+			time.Now(),
+		))
+
+		if section.ThinkTime > 0 {
+			select {
+			case <-ctx.Done():
+				return done, nil
+			case <-user.end:
+				return done, nil
+			case <-time.After(section.ThinkTime):
+			}
+		}
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oneee-playground/r2d2-tester/internal/job"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// openTestWorks opens a stream of cnt works on every pass, and counts the passes.
+func openTestWorks(cnt int, paths ...string) (openUserStream, *atomic.Int32) {
+	var passes atomic.Int32
+
+	open := func(ctx context.Context, user int) (<-chan taggedWork, <-chan error) {
+		passes.Add(1)
+
+		stream := make(chan taggedWork)
+		go func() {
+			defer close(stream)
+
+			for i := 0; i < cnt; i++ {
+				w := newTestWork()
+				if len(paths) > 0 {
+					w.Input.Path = paths[i%len(paths)]
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case stream <- taggedWork{work: w}:
+				}
+			}
+		}()
+
+		return stream, make(chan error, 1)
+	}
+
+	return open, &passes
+}
+
+func TestVirtualUser(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		requested []time.Time
+	)
+
+	target := newTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, time.Now())
+		mu.Unlock()
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	run := func(t *testing.T, section job.Section, open openUserStream) (userStats, time.Duration, error) {
+		mu.Lock()
+		requested = nil
+		mu.Unlock()
+
+		section.ID, section.Type = uuid.New(), job.TypeVirtualUser
+		e := newTestExecutor(t, target, section)
+
+		start := time.Now()
+		stats, err := e.testVirtualUser(context.Background(), section, nil, open, newProgress(zap.NewNop(), 0))
+		return stats, time.Since(start), err
+	}
+
+	t.Run("iterations", func(t *testing.T) {
+		open, passes := openTestWorks(4)
+
+		stats, _, err := run(t, job.Section{Users: 2, Iterations: 3}, open)
+		assert.NoError(t, err)
+		assert.Equal(t, 2*3*4, stats.completed)
+		assert.Equal(t, int32(2*3), passes.Load())
+	})
+
+	t.Run("single pass by default", func(t *testing.T) {
+		open, passes := openTestWorks(4)
+
+		stats, _, err := run(t, job.Section{Users: 2}, open)
+		assert.NoError(t, err)
+		assert.Equal(t, 2*4, stats.completed)
+		assert.Equal(t, int32(2), passes.Load())
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		open, passes := openTestWorks(2)
+
+		stats, took, err := run(t, job.Section{
+			Users: 2, Duration: 300 * time.Millisecond, ThinkTime: 20 * time.Millisecond,
+		}, open)
+		assert.NoError(t, err)
+		assert.Less(t, took, time.Second)
+		assert.Greater(t, stats.throughput, 0.0)
+
+		// Users loop over the works until the duration ends.
+		assert.Greater(t, stats.completed, 2*2)
+		assert.Greater(t, passes.Load(), int32(2))
+	})
+
+	t.Run("think time between passes", func(t *testing.T) {
+		open, _ := openTestWorks(1)
+
+		stats, _, err := run(t, job.Section{
+			Users: 1, Iterations: 3, ThinkTime: 100 * time.Millisecond,
+		}, open)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.completed)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if assert.Len(t, requested, 3) {
+			for i := 1; i < len(requested); i++ {
+				assert.GreaterOrEqual(t, requested[i].Sub(requested[i-1]), 100*time.Millisecond)
+			}
+		}
+	})
+
+	t.Run("failing user cancels others", func(t *testing.T) {
+		open, _ := openTestWorks(10, "/", "/", "/fail")
+
+		stats, took, err := run(t, job.Section{
+			Users: 3, Duration: 10 * time.Second, ThinkTime: 10 * time.Millisecond,
+		}, open)
+		if assert.Error(t, err) {
+			assert.Equal(t, failureStatus, categoryOf(err))
+		}
+		assert.Less(t, took, time.Second)
+		assert.GreaterOrEqual(t, stats.failed, 1)
+		assert.NotEmpty(t, stats.firstFailed)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			// Storage sends its error and closes the stream right away.
+			open := func(ctx context.Context, user int) (<-chan taggedWork, <-chan error) {
+				stream := make(chan taggedWork, 1)
+				errchan := make(chan error, 1)
+				stream <- taggedWork{work: newTestWork()}
+				errchan <- errors.Wrap(work.ErrCorrupt, "decoding work")
+				close(stream)
+				return stream, errchan
+			}
+
+			_, _, err := run(t, job.Section{Users: 2, Iterations: 5}, open)
+			if assert.Error(t, err) {
+				assert.Equal(t, failureIntegrity, categoryOf(err))
+			}
+		}
+	})
+}
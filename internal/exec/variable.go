@@ -0,0 +1,143 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/oneee-playground/r2d2-tester/internal/util/jsonpointer"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+// variablePattern matches references like {{boardID}}.
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// variables holds values captured from responses.
+// Nil variables means substitution and extraction are disabled.
+type variables map[string]string
+
+// expand substitutes references to captured variables with their escaped values.
+// References to others are left as they are, since they may be literal text.
+func (v variables) expand(s string, escape func(string) string) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variablePattern.FindStringSubmatch(ref)[1]
+
+		val, ok := v[name]
+		if !ok {
+			return ref
+		}
+
+		return escape(val)
+	})
+}
+
+// resolveInput returns copy of input with every variable reference substituted.
+func (v variables) resolveInput(input *work.Input) *work.Input {
+	if v == nil {
+		return input
+	}
+
+	var headers map[string]string
+	if input.Headers != nil {
+		headers = make(map[string]string, len(input.Headers))
+		for key, val := range input.Headers {
+			headers[key] = v.expand(val, noEscape)
+		}
+	}
+
+	resolved := &work.Input{
+		Method:  input.Method,
+		Path:    v.expand(input.Path, noEscape),
+		Headers: headers,
+		Body:    []byte(v.expand(string(input.Body), bodyEscaper(input))),
+	}
+
+	return resolved
+}
+
+func noEscape(s string) string { return s }
+
+// bodyEscaper escapes values for the content type of the body.
+// Body without content type is taken as JSON if it looks like one.
+func bodyEscaper(input *work.Input) func(string) string {
+	var contentType string
+	for key, val := range input.Headers {
+		if strings.EqualFold(key, "Content-Type") {
+			contentType = val
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return url.QueryEscape
+	case strings.HasSuffix(mediaType, "json"):
+		return escapeJSON
+	case contentType == "":
+		if trimmed := bytes.TrimSpace(input.Body); bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")) {
+			return escapeJSON
+		}
+	}
+
+	return noEscape
+}
+
+// escapeJSON escapes s to be put inside a JSON string.
+func escapeJSON(s string) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	// Strings are always encoded.
+	_ = enc.Encode(s)
+
+	b := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return string(b[1 : len(b)-1])
+}
+
+func (v variables) extract(header http.Header, body []byte, extractions []*work.Extraction) error {
+	if v == nil || len(extractions) == 0 {
+		return nil
+	}
+
+	var doc any
+	for _, extraction := range extractions {
+		var val string
+
+		switch extraction.Source {
+		case work.Extraction_HEADER:
+			if len(header.Values(extraction.Key)) == 0 {
+				return errors.Errorf("header %s not found for variable %s", extraction.Key, extraction.Name)
+			}
+			val = header.Get(extraction.Key)
+		case work.Extraction_BODY:
+			if doc == nil {
+				decoded, err := jsonpointer.Decode(body)
+				if err != nil {
+					return errors.Wrapf(err, "extracting variable %s", extraction.Name)
+				}
+				doc = decoded
+			}
+
+			found, err := jsonpointer.Get(doc, extraction.Key)
+			if err != nil {
+				return errors.Wrapf(err, "extracting variable %s", extraction.Name)
+			}
+
+			val = stringifyJSON(found)
+		default:
+			return errors.Errorf("unknown extraction source: %s", extraction.Source)
+		}
+
+		v[extraction.Name] = val
+	}
+
+	return nil
+}
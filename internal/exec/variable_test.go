@@ -0,0 +1,170 @@
+package exec
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariablesResolveInput(t *testing.T) {
+	vars := variables{"id": "3f2a", "token": "secret"}
+
+	input := &work.Input{
+		Method:  "PUT",
+		Path:    "/boards/{{id}}",
+		Headers: map[string]string{"Authorization": "Bearer {{ token }}"},
+		Body:    []byte(`{"id":"{{id}}"}`),
+	}
+
+	got := vars.resolveInput(input)
+
+	assert.Equal(t, "PUT", got.Method)
+	assert.Equal(t, "/boards/3f2a", got.Path)
+	assert.Equal(t, "Bearer secret", got.Headers["Authorization"])
+	assert.Equal(t, `{"id":"3f2a"}`, string(got.Body))
+
+	// Original input must be left untouched.
+	assert.Equal(t, "/boards/{{id}}", input.Path)
+
+	// Unknown references may be literal text.
+	got = vars.resolveInput(&work.Input{
+		Path: "/boards/{{missing}}",
+		Body: []byte(`{"template":"Hello {{ name }}"}`),
+	})
+	assert.Equal(t, "/boards/{{missing}}", got.Path)
+	assert.Equal(t, `{"template":"Hello {{ name }}"}`, string(got.Body))
+}
+
+func TestVariablesResolveInputEscape(t *testing.T) {
+	vars := variables{"title": `say "hi" \ bye`}
+
+	testcases := []struct {
+		desc    string
+		headers map[string]string
+		body    string
+		want    string
+	}{
+		{
+			desc:    "json",
+			headers: map[string]string{"content-type": "application/json; charset=utf-8"},
+			body:    `{"title":"{{title}}"}`,
+			want:    `{"title":"say \"hi\" \\ bye"}`,
+		},
+		{
+			desc: "json without content type",
+			body: `{"title":"{{title}}"}`,
+			want: `{"title":"say \"hi\" \\ bye"}`,
+		},
+		{
+			desc:    "form",
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:    `title={{title}}`,
+			want:    `title=say+%22hi%22+%5C+bye`,
+		},
+		{
+			desc:    "plain text",
+			headers: map[string]string{"Content-Type": "text/plain"},
+			body:    `{{title}}`,
+			want:    `say "hi" \ bye`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := vars.resolveInput(&work.Input{Headers: tc.headers, Body: []byte(tc.body)})
+			assert.Equal(t, tc.want, string(got.Body))
+
+			if strings.Contains(tc.desc, "json") {
+				assert.True(t, json.Valid(got.Body))
+			}
+		})
+	}
+}
+
+func TestVariablesResolveInputDisabled(t *testing.T) {
+	var vars variables
+
+	input := &work.Input{Path: "/boards/{{id}}"}
+
+	assert.Same(t, input, vars.resolveInput(input))
+}
+
+func TestVariablesExtract(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		header      http.Header
+		body        string
+		extractions []*work.Extraction
+		want        variables
+		wantErr     bool
+	}{
+		{
+			desc: "string from body",
+			body: `{"board":{"id":"3f2a"}}`,
+			extractions: []*work.Extraction{
+				{Name: "id", Source: work.Extraction_BODY, Key: "/board/id"},
+			},
+			want: variables{"id": "3f2a"},
+		},
+		{
+			desc: "number and object from body",
+			body: `{"id":12,"meta":{"a":1}}`,
+			extractions: []*work.Extraction{
+				{Name: "id", Source: work.Extraction_BODY, Key: "/id"},
+				{Name: "meta", Source: work.Extraction_BODY, Key: "/meta"},
+			},
+			want: variables{"id": "12", "meta": `{"a":1}`},
+		},
+		{
+			desc:   "header",
+			header: http.Header{"Location": []string{"/boards/3f2a"}},
+			extractions: []*work.Extraction{
+				{Name: "location", Source: work.Extraction_HEADER, Key: "location"},
+			},
+			want: variables{"location": "/boards/3f2a"},
+		},
+		{
+			desc:   "missing header",
+			header: http.Header{},
+			extractions: []*work.Extraction{
+				{Name: "location", Source: work.Extraction_HEADER, Key: "Location"},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "missing body value",
+			body: `{}`,
+			extractions: []*work.Extraction{
+				{Name: "id", Source: work.Extraction_BODY, Key: "/id"},
+			},
+			wantErr: true,
+		},
+		{
+			desc: "non-json body",
+			body: `hello`,
+			extractions: []*work.Extraction{
+				{Name: "id", Source: work.Extraction_BODY, Key: "/id"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			vars := make(variables)
+
+			err := vars.extract(tc.header, []byte(tc.body), tc.extractions)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, vars)
+			}
+		})
+	}
+}
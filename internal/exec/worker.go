@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oneee-playground/r2d2-tester/internal/work"
@@ -15,23 +17,156 @@ import (
 type worker struct {
 	target    *process
 	templates map[uuid.UUID]template
+	vars      variables
+	auth      *authenticator
 
 	httpClient *http.Client
 }
 
-func (w *worker) do(ctx context.Context, work *work.Work) error {
+// result describes how a work was done.
+type result struct {
+	attempts int
+	// first is the latency of the first attempt.
+	first time.Duration
+	// took is the time until the work passed or was given up.
+	took time.Duration
+	// trace is of the last attempt.
+	trace trace
+}
+
+// trace is what was sent and received in an attempt.
+type trace struct {
+	request    *work.Input
+	status     int
+	header     http.Header
+	body       []byte
+	assertions []*work.AssertionResult
+	// schema is the template schema the response was evaluated against.
+	schema *work.TemplatedSchema
+}
+
+// errRetryDeadline is the cause of retries reaching their deadline.
+var errRetryDeadline = errors.New("retry deadline exceeded")
+
+// do does the work, retrying it if the work has retry policy.
+// Policy with deadline but without max attempts retries until the deadline.
+// On failure, it returns the error of the last attempt.
+func (w *worker) do(ctx context.Context, work *work.Work) (result, error) {
+	policy := work.Retry
+	deadline := policy.GetDeadline().AsDuration()
+
+	// Zero means unlimited.
+	maxAttempts := 1
+	switch {
+	case policy.GetMaxAttempts() > 1:
+		maxAttempts = int(policy.MaxAttempts)
+	case policy.GetMaxAttempts() == 0 && deadline > 0:
+		maxAttempts = 0
+	}
+
+	if deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, deadline, errRetryDeadline)
+		defer cancel()
+	}
+
+	interval := policy.GetInterval().AsDuration()
+
+	// Patterns are compiled once for every attempt.
+	var exp expectation
+	var err error
+
+	exp.headerMatchers, err = compileHeaderMatchers(work.GetExpectedValue().GetHeaderMatchers())
+	if err != nil {
+		return result{}, categorize(failureDefinition, err)
+	}
+	exp.assertions, err = compileAssertions(work.GetExpectedValue().GetAssertions())
+	if err != nil {
+		return result{}, categorize(failureDefinition, err)
+	}
+
+	var res result
+	start := time.Now()
+
+	for {
+		attemptStart := time.Now()
+
+		res.trace = trace{}
+		err := w.attempt(ctx, work, exp, &res.trace)
+
+		res.attempts++
+		res.took = time.Since(start)
+		if res.attempts == 1 {
+			res.first = time.Since(attemptStart)
+		}
+
+		if err == nil {
+			return res, nil
+		}
+
+		if maxAttempts > 0 && res.attempts >= maxAttempts {
+			if maxAttempts > 1 {
+				err = errors.Wrapf(err, "giving up after %d attempts", res.attempts)
+			}
+			return res, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if context.Cause(ctx) != errRetryDeadline {
+				// Canceled from outside, not by the policy.
+				return res, errors.Wrapf(ctx.Err(), "retrying after %d attempts", res.attempts)
+			}
+			return res, errors.Wrapf(err, "retry deadline exceeded after %d attempts", res.attempts)
+		case <-time.After(interval):
+		}
+
+		if policy.Backoff > 0 {
+			interval = time.Duration(float64(interval) * policy.Backoff)
+		}
+	}
+}
+
+// expectation is the compiled part of the expected value.
+type expectation struct {
+	headerMatchers []headerMatcher
+	assertions     []assertion
+}
+
+// attempt sends the request and evaluates the response.
+// Exchanged messages are left in tr.
+func (w *worker) attempt(ctx context.Context, work *work.Work, exp expectation, tr *trace) error {
 	ctx, cancel := context.WithTimeout(ctx, work.Timeout.AsDuration())
 	defer cancel()
 
-	res, err := w.sendRequest(ctx, work.Input)
+	if work.ClearCookies {
+		if err := clearCookies(w.httpClient); err != nil {
+			return err
+		}
+	}
+
+	input := w.vars.resolveInput(work.Input)
+	tr.request = input
+
+	res, err := w.sendRequest(ctx, input)
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return categorize(failureTimeout, errors.New("deadline exceeded while waiting response"))
+		}
+		return categorize(failureConnection, errors.Wrap(err, "sending request"))
+	}
 
+	body, err := readBody(res.Body)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return errors.New("deadline exceeded while waiting response")
+			return categorize(failureTimeout, errors.New("deadline exceeded while reading response"))
 		}
-		return errors.Wrap(err, "sending request")
+		return categorize(failureConnection, err)
 	}
 
+	tr.status, tr.header, tr.body = res.StatusCode, res.Header, body
+
 	useTemplate := len(work.TemplateId) > 0
 	if useTemplate {
 		// Need to use template to evaluate.
@@ -45,56 +180,124 @@ func (w *worker) do(ctx context.Context, work *work.Work) error {
 			return errors.New("template not found")
 		}
 
-		schema, ok := template.schemaTable[res.StatusCode]
+		schema, ok := template.lookup(res.StatusCode)
 		if !ok {
-			return errors.Errorf("untemplated status code: %d", res.StatusCode)
+			return categorize(failureStatus, errors.Errorf("untemplated status code: %d", res.StatusCode))
 		}
 
+		tr.schema = schema.source
+
 		if err := evalHeaderAtLeast(res.Header, schema.headers); err != nil {
-			return err
+			return categorize(failureHeader, err)
 		}
-		if err := evalBodyJsonSchema(res.Body, schema.jsonSchema); err != nil {
-			return err
+		if err := evalHeaderMatchers(res.Header, schema.headerMatchers); err != nil {
+			return categorize(failureHeader, err)
+		}
+		if err := evalBodyJsonSchema(body, schema.jsonSchema); err != nil {
+			return categorize(failureBody, err)
 		}
 	} else {
 		// Expecting exact value.
 		expected := work.ExpectedValue
 
-		if err := evalStatuscode(res.StatusCode, int(expected.Status)); err != nil {
-			return err
+		if len(expected.Statuses) > 0 {
+			if err := evalStatuscodeIn(res.StatusCode, expected.Statuses); err != nil {
+				return categorize(failureStatus, err)
+			}
+		} else if err := evalStatuscode(res.StatusCode, int(expected.Status)); err != nil {
+			return categorize(failureStatus, err)
 		}
 		if err := evalHeaderAtLeast(res.Header, expected.Headers); err != nil {
-			return err
+			return categorize(failureHeader, err)
 		}
-		if err := evalBodyExact(res.Body, expected.Body); err != nil {
-			return err
+		if err := evalHeaderMatchers(res.Header, exp.headerMatchers); err != nil {
+			return categorize(failureHeader, err)
+		}
+		if err := evalCookies(res.Cookies(), expected.Cookies); err != nil {
+			return categorize(failureHeader, err)
+		}
+
+		// Assertions replace body comparison unless body is given too.
+		if len(expected.Assertions) == 0 || len(expected.Body) > 0 {
+			if err := evalBody(body, expected); err != nil {
+				return categorize(failureBody, err)
+			}
+		}
+		assertions, err := evalAssertions(body, exp.assertions)
+		tr.assertions = assertions
+		if err != nil {
+			return categorize(failureBody, err)
 		}
 	}
 
+	if err := w.vars.extract(res.Header, body, work.Extractions); err != nil {
+		return errors.Wrap(err, "extracting variables")
+	}
+
 	return nil
 }
 
+func readBody(body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading body")
+	}
+
+	return b, nil
+}
+
 func (w *worker) sendRequest(ctx context.Context, input *work.Input) (*http.Response, error) {
+	res, token, err := w.sendOnce(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := token != "" && w.auth.conf.RefreshOnUnauthorized
+	if refresh && res.StatusCode == http.StatusUnauthorized {
+		res.Body.Close()
+
+		if err := w.auth.refresh(ctx, token); err != nil {
+			return nil, errors.Wrap(err, "refreshing token")
+		}
+
+		res, _, err = w.sendOnce(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// sendOnce sends the request and returns token attached to it, if any.
+func (w *worker) sendOnce(ctx context.Context, input *work.Input) (*http.Response, string, error) {
 	url := fmt.Sprintf("http://%s:%d%s", w.target.Hostname, w.target.Port, input.Path)
 
 	request, err := http.NewRequestWithContext(ctx, input.Method, url, bytes.NewReader(input.Body))
 	if err != nil {
-		return nil, errors.Wrap(err, "creating new request")
+		return nil, "", errors.Wrap(err, "creating new request")
 	}
 
 	for key, val := range input.Headers {
 		request.Header.Set(key, val)
 	}
 
+	var token string
+	if w.auth != nil {
+		token = w.auth.authorize(request)
+	}
+
 	res, err := w.httpClient.Do(request)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			err = context.Cause(ctx)
 		}
-		return nil, errors.Wrap(err, "sending request")
+		return nil, "", errors.Wrap(err, "sending request")
 	}
 
-	return res, nil
+	return res, token, nil
 }
 
 type concurrentWorker struct {
@@ -102,12 +305,17 @@ type concurrentWorker struct {
 
 	index       int
 	inputStream chan *work.Work
+
+	// last is the result of the latest work.
+	// It is safe to read after receiving index from doneStream.
+	last result
+	// lastErr is the failure of the latest work.
+	lastErr error
 }
 
-func (cw *concurrentWorker) run(
-	ctx context.Context, wg *sync.WaitGroup,
-	doneStream chan<- int, errchan chan<- error,
-) {
+// run does works until the context is canceled.
+// Failed works are reported through doneStream with lastErr set.
+func (cw *concurrentWorker) run(ctx context.Context, wg *sync.WaitGroup, doneStream chan<- int) {
 	defer wg.Done()
 	defer close(cw.inputStream)
 
@@ -120,14 +328,13 @@ func (cw *concurrentWorker) run(
 		case work = <-cw.inputStream:
 		}
 
-		if err := cw.underlying.do(ctx, work); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return
-			}
-			errchan <- err
+		res, err := cw.underlying.do(ctx, work)
+		if errors.Is(err, context.Canceled) {
 			return
 		}
 
+		cw.last, cw.lastErr = res, err
+
 		doneStream <- cw.index
 	}
 }
@@ -137,20 +344,18 @@ type workerPool struct {
 	wg      *sync.WaitGroup
 
 	doneStream chan int
-	errchan    chan error
 
 	closeFunc func()
 }
 
 func newWorkerPool(
 	count int, target *process, templates map[uuid.UUID]template,
-	httpClient *http.Client,
+	httpClient *http.Client, auth *authenticator,
 ) *workerPool {
 	pool := &workerPool{
 		workers:    make([]*concurrentWorker, count),
 		wg:         new(sync.WaitGroup),
 		doneStream: make(chan int, count),
-		errchan:    make(chan error, count),
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -164,12 +369,13 @@ func newWorkerPool(
 			underlying: &worker{
 				target:     target,
 				templates:  templates,
+				auth:       auth,
 				httpClient: httpClient,
 			},
 			inputStream: make(chan *work.Work),
 		}
 
-		go cw.run(ctx, pool.wg, pool.doneStream, pool.errchan)
+		go cw.run(ctx, pool.wg, pool.doneStream)
 
 		pool.workers[i] = cw
 		pool.doneStream <- i
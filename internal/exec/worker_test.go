@@ -0,0 +1,119 @@
+package exec
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestWorkerDoRetry(t *testing.T) {
+	var requested atomic.Int32
+
+	// Board becomes visible after the third request.
+	target := newTestTarget(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requested.Add(1) < 3 {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	newWork := func(policy *work.RetryPolicy) *work.Work {
+		return &work.Work{
+			Input:         &work.Input{Method: "GET", Path: "/boards/1"},
+			ExpectedValue: &work.Expected{Status: http.StatusOK},
+			Timeout:       durationpb.New(time.Second),
+			Retry:         policy,
+		}
+	}
+
+	w := &worker{target: target, httpClient: http.DefaultClient}
+
+	t.Run("without policy", func(t *testing.T) {
+		requested.Store(0)
+
+		res, err := w.do(context.Background(), newWork(nil))
+		assert.Error(t, err)
+		assert.Equal(t, 1, res.attempts)
+	})
+
+	t.Run("passes eventually", func(t *testing.T) {
+		requested.Store(0)
+
+		res, err := w.do(context.Background(), newWork(&work.RetryPolicy{
+			MaxAttempts: 5,
+			Interval:    durationpb.New(time.Millisecond),
+			Backoff:     2,
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, res.attempts)
+		assert.LessOrEqual(t, res.first, res.took)
+	})
+
+	t.Run("exhausts attempts", func(t *testing.T) {
+		requested.Store(0)
+
+		res, err := w.do(context.Background(), newWork(&work.RetryPolicy{
+			MaxAttempts: 2,
+			Interval:    durationpb.New(time.Millisecond),
+		}))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unmatching status code")
+		}
+		assert.Equal(t, 2, res.attempts)
+	})
+
+	t.Run("retries until deadline", func(t *testing.T) {
+		requested.Store(0)
+
+		res, err := w.do(context.Background(), newWork(&work.RetryPolicy{
+			Interval: durationpb.New(time.Millisecond),
+			Deadline: durationpb.New(time.Second),
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, res.attempts)
+	})
+
+	t.Run("gives up at deadline", func(t *testing.T) {
+		requested.Store(-100)
+
+		res, err := w.do(context.Background(), newWork(&work.RetryPolicy{
+			Interval: durationpb.New(10 * time.Millisecond),
+			Deadline: durationpb.New(50 * time.Millisecond),
+		}))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "retry deadline exceeded")
+			assert.NotErrorIs(t, err, context.Canceled)
+		}
+		assert.Greater(t, res.attempts, 1)
+	})
+
+	t.Run("canceled while retrying", func(t *testing.T) {
+		requested.Store(-100)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(30*time.Millisecond, cancel)
+
+		_, err := w.do(ctx, newWork(&work.RetryPolicy{
+			Interval: durationpb.New(10 * time.Millisecond),
+			Deadline: durationpb.New(time.Second),
+		}))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("exceeds deadline", func(t *testing.T) {
+		requested.Store(0)
+
+		res, err := w.do(context.Background(), newWork(&work.RetryPolicy{
+			MaxAttempts: 5,
+			Interval:    durationpb.New(time.Hour),
+			Deadline:    durationpb.New(10 * time.Millisecond),
+		}))
+		assert.Error(t, err)
+		assert.Equal(t, 1, res.attempts)
+	})
+}
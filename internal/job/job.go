@@ -1,12 +1,19 @@
 package job
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SectionType string
 
 const (
 	TypeScenario SectionType = "SCENARIO"
 	TypeLoad     SectionType = "LOAD"
+	// TypeVirtualUser runs fixed number of users looping over works.
+	// Throughput is the outcome, unlike LOAD.
+	TypeVirtualUser SectionType = "VIRTUAL_USER"
 )
 
 type Resource struct {
@@ -22,6 +29,135 @@ type Section struct {
 	ID   uuid.UUID   `json:"id"`
 	Type SectionType `json:"type"`
 	RPM  uint64      `json:"rpm"`
+
+	// Weight of the section in the total score. Zero is regarded as 1.
+	Weight float64 `json:"weight"`
+	// Scoring decides partial credit of the section.
+	// Default rule of the section type is used if it is nil.
+	Scoring *Scoring `json:"scoring"`
+
+	// Session keeps cookies across works inside the section.
+	// Each virtual user has its own session.
+	Session bool `json:"session"`
+	// Auth logs in before the section, and injects the token to every work.
+	Auth *Auth `json:"auth"`
+
+	Setup    []Hook `json:"setup"`
+	Teardown []Hook `json:"teardown"`
+
+	// Streams mixes works of sub-sections by weight in LOAD section.
+	// Section's own works are used if it is empty.
+	Streams []Stream `json:"streams"`
+	// Seed makes the mix deterministic.
+	Seed int64 `json:"seed"`
+
+	// Profile shapes offered load over time in LOAD section.
+	// RPM is used as a flat load if it is empty.
+	Profile []Stage `json:"profile"`
+	// Thresholds decide whether LOAD section passes after the run.
+	Thresholds *Thresholds `json:"thresholds"`
+	// ErrorBudget is the number of failed works LOAD section tolerates.
+	// Section aborts once it is exhausted.
+	// If it is nil, failures are unlimited when error rate is limited, and none otherwise.
+	ErrorBudget *int `json:"errorBudget"`
+
+	// Users is the number of virtual users in VIRTUAL_USER section.
+	Users uint64 `json:"users"`
+	// ThinkTime is the pause of each user between works.
+	ThinkTime time.Duration `json:"thinkTime"`
+	// Iterations is the number of passes each user makes over the works.
+	Iterations uint64 `json:"iterations"`
+	// Duration bounds VIRTUAL_USER section. Users loop over the works until it ends.
+	// Without both Iterations and Duration, each user makes one pass.
+	Duration time.Duration `json:"duration"`
+}
+
+type Transition string
+
+const (
+	TransitionInstant Transition = "instant"
+	TransitionLinear  Transition = "linear"
+)
+
+// Stage moves load to TargetRPM, and holds it until Duration passes.
+// Linear transition ramps the load from the previous stage's target over the stage.
+type Stage struct {
+	Duration   time.Duration `json:"duration"`
+	TargetRPM  uint64        `json:"targetRPM"`
+	Transition Transition    `json:"transition"`
+}
+
+type ScoringRule string
+
+const (
+	// ScoringAllOrNothing gives full score only if the section passed.
+	// It is the default of VIRTUAL_USER section.
+	ScoringAllOrNothing ScoringRule = "all-or-nothing"
+	// ScoringPassRatio gives the fraction of passing works.
+	// It is the default of SCENARIO section.
+	ScoringPassRatio ScoringRule = "pass-ratio"
+	// ScoringSLOCurve grades each threshold on a curve, and averages them.
+	// It is the default of LOAD section.
+	ScoringSLOCurve ScoringRule = "slo-curve"
+)
+
+type Scoring struct {
+	Rule ScoringRule `json:"rule"`
+	// Tolerance is how far past a threshold, relative to its limit,
+	// the threshold's score drops linearly to zero in slo-curve.
+	// Breached thresholds score zero if it is zero.
+	Tolerance float64 `json:"tolerance"`
+}
+
+// Thresholds are SLOs of LOAD section. Unset fields are not checked.
+type Thresholds struct {
+	// MaxErrorRate is the ratio of failed works, from 0 to 1.
+	MaxErrorRate *float64 `json:"maxErrorRate"`
+	// Latency limits response time, which includes queueing delay.
+	Latency []LatencyThreshold `json:"latency"`
+	// MinRPM is the lowest achieved throughput.
+	MinRPM float64 `json:"minRPM"`
+	// MaxMissedDues limits dues missed because the target was too slow.
+	MaxMissedDues *int `json:"maxMissedDues"`
+}
+
+// LatencyThreshold requires latency at Percentile to be under Max.
+// e.g. {95, 200ms} means p95 < 200ms.
+type LatencyThreshold struct {
+	Percentile float64       `json:"percentile"`
+	Max        time.Duration `json:"max"`
+}
+
+// Stream is a sub-section that has its own works and templates.
+type Stream struct {
+	ID     uuid.UUID `json:"id"`
+	Weight uint64    `json:"weight"`
+}
+
+// Hook runs a command inside the resource container.
+type Hook struct {
+	Resource string   `json:"resource"`
+	Command  []string `json:"command"`
+	// File is fed to the command's stdin if given.
+	// It is stored next to the section's works.
+	File string `json:"file"`
+	// Timeout bounds the command. Default is used if it is zero.
+	Timeout time.Duration `json:"timeout"`
+}
+
+type Auth struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+
+	// Token is taken from the JSON pointer into the body,
+	// or from the header if TokenHeader is given.
+	TokenPointer string `json:"tokenPointer"`
+	TokenHeader  string `json:"tokenHeader"`
+
+	// RefreshOnUnauthorized logs in again and retries once on 401.
+	RefreshOnUnauthorized bool `json:"refreshOnUnauthorized"`
 }
 
 type Submission struct {
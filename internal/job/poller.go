@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 )
@@ -13,7 +15,8 @@ import (
 var NoErrEmptyJobs = errors.New("jobs are empty")
 
 type Poller interface {
-	Poll(ctx context.Context) (id string, job Job, err error)
+	// Poll receives a job. deliveries is how many times it has been received, including this one.
+	Poll(ctx context.Context) (id string, job Job, deliveries int, err error)
 	MarkAsDone(ctx context.Context, id string) (err error)
 }
 
@@ -29,16 +32,21 @@ func NewPoller(sqs *sqs.Client, queueURL string) *poller {
 	}
 }
 
-func (p *poller) Poll(ctx context.Context) (id string, job Job, err error) {
-	input := &sqs.ReceiveMessageInput{QueueUrl: aws.String(p.queueURL)}
+func (p *poller) Poll(ctx context.Context) (id string, job Job, deliveries int, err error) {
+	input := &sqs.ReceiveMessageInput{
+		QueueUrl: aws.String(p.queueURL),
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameApproximateReceiveCount,
+		},
+	}
 
 	result, err := p.client.ReceiveMessage(ctx, input)
 	if err != nil {
-		return "", Job{}, errors.Wrap(err, "receiving message")
+		return "", Job{}, 0, errors.Wrap(err, "receiving message")
 	}
 
 	if len(result.Messages) == 0 {
-		return "", Job{}, NoErrEmptyJobs
+		return "", Job{}, 0, NoErrEmptyJobs
 	}
 
 	msg := result.Messages[0]
@@ -47,12 +55,22 @@ func (p *poller) Poll(ctx context.Context) (id string, job Job, err error) {
 
 	var decoded Job
 	if err := json.Unmarshal([]byte(*msg.Body), &decoded); err != nil {
-		return "", Job{}, errors.Wrap(err, "failed to unmarshal job")
+		return "", Job{}, 0, errors.Wrap(err, "failed to unmarshal job")
 	}
 
 	log.Println(decoded)
 
-	return *msg.ReceiptHandle, decoded, nil
+	return *msg.ReceiptHandle, decoded, receiveCount(msg), nil
+}
+
+// receiveCount returns how many times the message has been received.
+// It is 1 if unknown.
+func receiveCount(msg types.Message) int {
+	n, err := strconv.Atoi(msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
 }
 
 func (p *poller) MarkAsDone(ctx context.Context, id string) (err error) {
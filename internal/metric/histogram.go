@@ -0,0 +1,137 @@
+package metric
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Latencies are recorded in microseconds.
+const (
+	histogramUnit    = time.Microsecond
+	histogramMin     = int64(1)
+	histogramMax     = int64(time.Hour / histogramUnit)
+	histogramSigFigs = 3
+)
+
+// Summary summarizes latency distribution.
+type Summary struct {
+	Count int64         `json:"count"`
+	Min   time.Duration `json:"min"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	P999  time.Duration `json:"p999"`
+	Max   time.Duration `json:"max"`
+}
+
+// Fields returns summary as fields of a point.
+func (s Summary) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"count": s.Count,
+		"min":   s.Min.Nanoseconds(),
+		"mean":  s.Mean.Nanoseconds(),
+		"p50":   s.P50.Nanoseconds(),
+		"p90":   s.P90.Nanoseconds(),
+		"p95":   s.P95.Nanoseconds(),
+		"p99":   s.P99.Nanoseconds(),
+		"p99.9": s.P999.Nanoseconds(),
+		"max":   s.Max.Nanoseconds(),
+	}
+}
+
+// Percentile returns latency at the percentile, e.g. 95 for p95.
+func (s Summary) Percentile(p float64) (time.Duration, bool) {
+	switch p {
+	case 50:
+		return s.P50, true
+	case 90:
+		return s.P90, true
+	case 95:
+		return s.P95, true
+	case 99:
+		return s.P99, true
+	case 99.9:
+		return s.P999, true
+	case 100:
+		return s.Max, true
+	}
+
+	return 0, false
+}
+
+// Histogram is high dynamic range histogram of latencies.
+// It is safe for concurrent use.
+type Histogram struct {
+	mu   sync.Mutex
+	hist *hdrhistogram.Histogram
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{
+		hist: hdrhistogram.New(histogramMin, histogramMax, histogramSigFigs),
+	}
+}
+
+// Record records latency. It is clamped into trackable range.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordCorrected(d, 0, 0)
+}
+
+// RecordCorrected records latency, and also latencies of the requests
+// skipped while it was pending. They were due every interval after it,
+// and would have been answered when it was.
+func (h *Histogram) RecordCorrected(d, interval time.Duration, skipped int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := 0; i <= skipped; i++ {
+		v := d - time.Duration(i)*interval
+		if i > 0 && v <= 0 {
+			break
+		}
+
+		// Value is always in range.
+		_ = h.hist.RecordValue(clampValue(v))
+	}
+}
+
+func clampValue(d time.Duration) int64 {
+	return min(max(int64(d/histogramUnit), histogramMin), histogramMax)
+}
+
+// ValueAt returns latency at the percentile, from 0 to 100.
+func (h *Histogram) ValueAt(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return time.Duration(h.hist.ValueAtPercentile(p)) * histogramUnit
+}
+
+func (h *Histogram) Summary() Summary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.hist.TotalCount() == 0 {
+		return Summary{}
+	}
+
+	at := func(p float64) time.Duration {
+		return time.Duration(h.hist.ValueAtPercentile(p)) * histogramUnit
+	}
+
+	return Summary{
+		Count: h.hist.TotalCount(),
+		Min:   time.Duration(h.hist.Min()) * histogramUnit,
+		Mean:  time.Duration(h.hist.Mean() * float64(histogramUnit)),
+		P50:   at(50),
+		P90:   at(90),
+		P95:   at(95),
+		P99:   at(99),
+		P999:  at(99.9),
+		Max:   time.Duration(h.hist.Max()) * histogramUnit,
+	}
+}
@@ -0,0 +1,78 @@
+package metric
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram()
+	assert.Equal(t, Summary{}, h.Summary())
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 1000; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			h.Record(time.Duration(i) * time.Millisecond)
+		}(i)
+	}
+	wg.Wait()
+
+	s := h.Summary()
+
+	// Values are accurate up to 3 significant figures.
+	within := func(expected, actual time.Duration) {
+		assert.InEpsilon(t, float64(expected), float64(actual), 0.001)
+	}
+
+	assert.Equal(t, int64(1000), s.Count)
+	within(time.Millisecond, s.Min)
+	within(500500*time.Microsecond, s.Mean)
+	within(500*time.Millisecond, s.P50)
+	within(900*time.Millisecond, s.P90)
+	within(950*time.Millisecond, s.P95)
+	within(990*time.Millisecond, s.P99)
+	within(999*time.Millisecond, s.P999)
+	within(time.Second, s.Max)
+
+	p95, ok := s.Percentile(95)
+	assert.True(t, ok)
+	assert.Equal(t, s.P95, p95)
+
+	_, ok = s.Percentile(42)
+	assert.False(t, ok)
+
+	within(750*time.Millisecond, h.ValueAt(75))
+}
+
+func TestHistogramClamp(t *testing.T) {
+	h := NewHistogram()
+	h.Record(0)
+	h.Record(2 * time.Hour)
+
+	s := h.Summary()
+	assert.Equal(t, int64(2), s.Count)
+	assert.InEpsilon(t, float64(time.Microsecond), float64(s.Min), 0.001)
+	assert.InEpsilon(t, float64(time.Hour), float64(s.Max), 0.001)
+}
+
+func TestHistogramRecordCorrected(t *testing.T) {
+	h := NewHistogram()
+
+	// Two requests due every 100ms were skipped while it took a second.
+	h.RecordCorrected(time.Second, 100*time.Millisecond, 2)
+
+	s := h.Summary()
+	assert.Equal(t, int64(3), s.Count)
+	assert.InEpsilon(t, float64(800*time.Millisecond), float64(s.Min), 0.001)
+	assert.InEpsilon(t, float64(time.Second), float64(s.Max), 0.001)
+
+	// Skipped ones after the latency ended are not recorded.
+	h = NewHistogram()
+	h.RecordCorrected(150*time.Millisecond, 100*time.Millisecond, 5)
+	assert.Equal(t, int64(2), h.Summary().Count)
+}
@@ -0,0 +1,71 @@
+package record
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+	protofmt "github.com/oneee-playground/r2d2-tester/internal/util/proto"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+const _filepathResultSuffix = ".results"
+
+// Path returns path of the results file of the submission.
+func Path(root string, submissionID uuid.UUID) string {
+	return filepath.Join(root, submissionID.String()+_filepathResultSuffix)
+}
+
+// Writer writes size-prefixed result records into a file.
+// It is safe for concurrent use.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+}
+
+// NewFileWriter creates the file, truncating previous results if any.
+func NewFileWriter(path string) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "creating result directory")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating result file")
+	}
+
+	return &Writer{file: file, buf: bufio.NewWriter(file)}, nil
+}
+
+func (w *Writer) Write(r *work.Result) error {
+	b, err := protofmt.MarshalWithSize(r)
+	if err != nil {
+		return errors.Wrap(err, "marshaling result")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.buf.Write(b); err != nil {
+		return errors.Wrap(err, "writing result")
+	}
+
+	return nil
+}
+
+// Close flushes buffered results and closes the file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return errors.Wrap(err, "flushing results")
+	}
+
+	return w.file.Close()
+}
@@ -0,0 +1,64 @@
+package record
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	protofmt "github.com/oneee-playground/r2d2-tester/internal/util/proto"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	submissionID := uuid.New()
+	path := Path(filepath.Join(t.TempDir(), "results"), submissionID)
+
+	w, err := NewFileWriter(path)
+	require.NoError(t, err)
+
+	cnt := 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < cnt; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, w.Write(&work.Result{Passed: i%2 == 0, Status: 200}))
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, w.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	dec := protofmt.NewDecoder(bufio.NewReader(file))
+
+	passed := 0
+	for i := 0; ; i++ {
+		r := new(work.Result)
+
+		err := dec.Decode(r)
+		if errors.Is(err, io.EOF) {
+			assert.Equal(t, cnt, i)
+			break
+		}
+		require.NoError(t, err)
+
+		assert.Equal(t, uint32(200), r.Status)
+		if r.Passed {
+			passed++
+		}
+	}
+
+	assert.Equal(t, cnt/2, passed)
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	ID       string      `xml:"id,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report in JUnit XML.
+// Each section is a test suite. Skipped sections have a single skipped case.
+func WriteJUnit(w io.Writer, r Report) error {
+	suites := junitSuites{
+		Name:   r.SubmissionID.String(),
+		Time:   seconds(r.Took),
+		Suites: make([]junitSuite, len(r.Sections)),
+	}
+
+	for idx, section := range r.Sections {
+		suite := junitSuite{
+			Name: fmt.Sprintf("%s %s", section.Type, section.ID),
+			ID:   strconv.Itoa(idx),
+			Time: seconds(section.Took),
+		}
+
+		if section.Skipped {
+			suite.Cases = []junitCase{{
+				Name:      "section",
+				Classname: suite.Name,
+				Time:      seconds(0),
+				Skipped:   &struct{}{},
+			}}
+			suite.Skipped = 1
+		}
+
+		for _, c := range section.Cases {
+			jc := junitCase{
+				Name:      c.Name,
+				Classname: suite.Name,
+				Time:      seconds(c.Took),
+				SystemOut: c.Output,
+			}
+
+			if c.Failure != nil {
+				jc.Failure = &junitFailure{
+					Message: c.Failure.Message,
+					Type:    c.Failure.Category,
+					Text:    c.Failure.Message,
+				}
+				suite.Failures++
+			}
+
+			suite.Cases = append(suite.Cases, jc)
+		}
+
+		suite.Tests = len(suite.Cases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites[idx] = suite
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Version of the JSON report schema.
+// It is bumped on every breaking change.
+const Version = 1
+
+const (
+	_filenameJUnit = "junit.xml"
+	_filenameJSON  = "report.json"
+)
+
+// Report is the outcome of a submission.
+type Report struct {
+	Version      int           `json:"version"`
+	SubmissionID uuid.UUID     `json:"submissionID"`
+	Success      bool          `json:"success"`
+	Took         time.Duration `json:"took"`
+	Error        string        `json:"error,omitempty"`
+	Sections     []Section     `json:"sections"`
+}
+
+// Section is a test suite. Sections not reached, e.g. on cancellation, are skipped.
+type Section struct {
+	ID      uuid.UUID     `json:"id"`
+	Type    string        `json:"type"`
+	Took    time.Duration `json:"took"`
+	Skipped bool          `json:"skipped"`
+	Cases   []Case        `json:"cases"`
+}
+
+// Case is a work, or aggregated works of load sections.
+type Case struct {
+	Name    string        `json:"name"`
+	Took    time.Duration `json:"took"`
+	Failure *Failure      `json:"failure,omitempty"`
+	// Output is extra information. e.g. stats of load sections.
+	Output string `json:"output,omitempty"`
+}
+
+type Failure struct {
+	Message string `json:"message"`
+	// Category tells why it failed. e.g. status, body, timeout.
+	Category string `json:"category,omitempty"`
+}
+
+// Paths are where reports are written.
+type Paths struct {
+	JUnit string
+	JSON  string
+}
+
+// Write writes both JUnit and JSON reports into directory of the submission under root.
+func Write(root string, r Report) (Paths, error) {
+	dir := filepath.Join(root, r.SubmissionID.String())
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Paths{}, errors.Wrap(err, "creating report directory")
+	}
+
+	paths := Paths{
+		JUnit: filepath.Join(dir, _filenameJUnit),
+		JSON:  filepath.Join(dir, _filenameJSON),
+	}
+
+	if err := writeFile(paths.JUnit, r, WriteJUnit); err != nil {
+		return Paths{}, errors.Wrap(err, "writing junit report")
+	}
+	if err := writeFile(paths.JSON, r, WriteJSON); err != nil {
+		return Paths{}, errors.Wrap(err, "writing json report")
+	}
+
+	return paths, nil
+}
+
+func writeFile(path string, r Report, write func(io.Writer, Report) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file, r); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// WriteJSON writes the report in JSON.
+func WriteJSON(w io.Writer, r Report) error {
+	r.Version = Version
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReport() Report {
+	return Report{
+		SubmissionID: uuid.New(),
+		Took:         3 * time.Second,
+		Error:        "testing SCENARIO: unmatching status code",
+		Sections: []Section{
+			{
+				ID:   uuid.New(),
+				Type: "SCENARIO",
+				Took: time.Second,
+				Cases: []Case{
+					{Name: "GET /boards", Took: 10 * time.Millisecond},
+					{
+						Name:    "POST /boards",
+						Took:    20 * time.Millisecond,
+						Failure: &Failure{Message: "unmatching status code", Category: "status"},
+					},
+				},
+			},
+			{ID: uuid.New(), Type: "LOAD", Skipped: true},
+		},
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	r := newTestReport()
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, WriteJUnit(buf, r))
+
+	var suites junitSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, "3.000", suites.Time)
+
+	if assert.Len(t, suites.Suites, 2) {
+		scenario := suites.Suites[0]
+		assert.Equal(t, 2, scenario.Tests)
+		assert.Equal(t, 1, scenario.Failures)
+		assert.Nil(t, scenario.Cases[0].Failure)
+		if assert.NotNil(t, scenario.Cases[1].Failure) {
+			assert.Equal(t, "unmatching status code", scenario.Cases[1].Failure.Message)
+			assert.Equal(t, "status", scenario.Cases[1].Failure.Type)
+		}
+
+		load := suites.Suites[1]
+		assert.Equal(t, 1, load.Skipped)
+		if assert.Len(t, load.Cases, 1) {
+			assert.NotNil(t, load.Cases[0].Skipped)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r := newTestReport()
+
+	paths, err := Write(t.TempDir(), r)
+	require.NoError(t, err)
+
+	_, err = os.Stat(paths.JUnit)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(paths.JSON)
+	require.NoError(t, err)
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	r.Version = Version
+	assert.Equal(t, r, decoded)
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/oneee-playground/r2d2-tester/internal/exec"
 	"github.com/oneee-playground/r2d2-tester/internal/job"
 	"github.com/oneee-playground/r2d2-tester/internal/metric"
+	"github.com/oneee-playground/r2d2-tester/internal/record"
+	"github.com/oneee-playground/r2d2-tester/internal/report"
 	"github.com/oneee-playground/r2d2-tester/internal/work"
 	"go.uber.org/zap"
 )
@@ -23,6 +25,36 @@ type ServerOpts struct {
 	WorkStorage    work.Storage
 	MetricStorage  *metric.Storage
 	Docker         client.APIClient
+
+	// ResultStoragePath is where result records are written.
+	// Records are not kept if it is empty.
+	ResultStoragePath string
+	// ArtifactPath is where reports are written.
+	// Reports are not written if it is empty.
+	ArtifactPath string
+	// MaxAttempts is how many times a job aborted by internal failure is delivered.
+	// It is given up and published as aborted after the last one. Default is 3.
+	MaxAttempts int
+}
+
+const defaultMaxAttempts = 3
+
+func (s *Server) maxAttempts() int {
+	if s.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return s.MaxAttempts
+}
+
+func verdict(result event.Result, aborted bool) event.Verdict {
+	switch {
+	case aborted:
+		return event.VerdictAborted
+	case result.Success:
+		return event.VerdictPassed
+	default:
+		return event.VerdictFailed
+	}
 }
 
 type Server struct {
@@ -50,7 +82,7 @@ func (s *Server) Run(ctx context.Context) error {
 		case <-ticker.C:
 		}
 
-		id, received, err := s.JobPoller.Poll(ctx)
+		id, received, deliveries, err := s.JobPoller.Poll(ctx)
 		if err != nil {
 			if err != job.NoErrEmptyJobs {
 				s.log.Error("failed to poll a job", zap.Error(err))
@@ -78,19 +110,83 @@ func (s *Server) Run(ctx context.Context) error {
 			MetricStorage: s.MetricStorage,
 		}
 
-		err = exec.NewExecutor(opts).Execute(ctx, received)
-		if err != nil {
-			s.log.Error("failed to execute a job", zap.Error(err))
+		var resultsPath string
+		if s.ResultStoragePath != "" {
+			path := record.Path(s.ResultStoragePath, submissionID)
+
+			records, err := record.NewFileWriter(path)
+			if err != nil {
+				s.log.Error("failed to create result file", zap.Error(err))
+			} else {
+				opts.Results, resultsPath = records, path
+			}
+		}
+
+		executor := exec.NewExecutor(opts)
+
+		result := executor.Execute(ctx, received)
+
+		// Internal failures are not the submission's fault. It isn't judged,
+		// and the job is left to be delivered again until it runs out of attempts.
+		aborted := result.Failure != nil && result.Failure.Internal
+		retry := aborted && deliveries < s.maxAttempts()
+		switch {
+		case aborted:
+			s.log.Error("job aborted by internal failure",
+				zap.String("category", result.Failure.Category),
+				zap.String("error", result.Failure.Message),
+				zap.Int("deliveries", deliveries),
+				zap.Bool("retry", retry),
+			)
+		case result.Failure != nil:
+			s.log.Error("failed to execute a job", zap.String("error", result.Failure.Message))
+		}
+
+		if opts.Results != nil {
+			if err := opts.Results.Close(); err != nil {
+				s.log.Error("failed to close result file", zap.Error(err))
+			}
+		}
+
+		if retry {
+			continue
 		}
 
 		event := event.TestEvent{
 			ID:      submissionID,
-			Success: err == nil,
+			Verdict: verdict(result, aborted),
 			Took:    time.Since(start),
+			Result:  result,
+			Latency: executor.LatencySummaries(),
+
+			Thresholds: executor.ThresholdResults(),
+			Results:    resultsPath,
 		}
 
-		if err != nil {
-			event.Extra = err.Error()
+		if result.Failure != nil {
+			event.Extra = result.Failure.Message
+		}
+
+		if !aborted {
+			event.Success = &result.Success
+			event.Score = executor.Score()
+		}
+
+		if s.ArtifactPath != "" && !aborted {
+			r := report.Report{
+				SubmissionID: submissionID,
+				Success:      result.Success,
+				Took:         event.Took,
+				Error:        event.Extra,
+				Sections:     executor.ReportSections(),
+			}
+
+			paths, err := report.Write(s.ArtifactPath, r)
+			if err != nil {
+				s.log.Error("failed to write reports", zap.Error(err))
+			} else {
+				event.JUnitReport, event.JSONReport = paths.JUnit, paths.JSON
+			}
 		}
 
 		if err := s.JobPoller.MarkAsDone(ctx, id); err != nil {
@@ -0,0 +1,145 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNotFound = errors.New("value not found")
+
+// Decode decodes JSON document while preserving numbers as json.Number.
+// Anything but whitespace after the document is an error.
+func Decode(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "decoding json")
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.Errorf("unexpected data after json at offset %d", dec.InputOffset())
+	}
+
+	return doc, nil
+}
+
+// Parse splits RFC 6901 JSON pointer into unescaped reference tokens.
+func Parse(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Errorf("malformed json pointer: %s", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for idx, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[idx] = strings.ReplaceAll(token, "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// Get returns the value inside doc referenced by pointer.
+func Get(doc any, pointer string) (any, error) {
+	tokens, err := Parse(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := doc
+	for _, token := range tokens {
+		switch v := cur.(type) {
+		case map[string]any:
+			val, ok := v[token]
+			if !ok {
+				return nil, errors.Wrapf(ErrNotFound, "key %q", token)
+			}
+			cur = val
+		case []any:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, errors.Wrapf(ErrNotFound, "index %q", token)
+			}
+			cur = v[idx]
+		default:
+			return nil, errors.Wrapf(ErrNotFound, "token %q on scalar", token)
+		}
+	}
+
+	return cur, nil
+}
+
+// Join builds JSON pointer out of reference tokens.
+func Join(tokens ...string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		token = strings.ReplaceAll(token, "~", "~0")
+		sb.WriteString("/" + strings.ReplaceAll(token, "/", "~1"))
+	}
+
+	return sb.String()
+}
+
+// Delete removes the value referenced by pointer from doc.
+// Token '*' matches every key or index. Missing values are ignored.
+func Delete(doc any, pointer string) (any, error) {
+	tokens, err := Parse(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	return deleteTokens(doc, tokens), nil
+}
+
+func deleteTokens(cur any, tokens []string) any {
+	token, last := tokens[0], len(tokens) == 1
+
+	switch v := cur.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if token != "*" && token != key {
+				continue
+			}
+			if last {
+				delete(v, key)
+			} else {
+				v[key] = deleteTokens(val, tokens[1:])
+			}
+		}
+	case []any:
+		if token == "*" {
+			if last {
+				return v[:0]
+			}
+			for idx := range v {
+				v[idx] = deleteTokens(v[idx], tokens[1:])
+			}
+			return v
+		}
+
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= len(v) {
+			return v
+		}
+		if last {
+			return append(v[:idx], v[idx+1:]...)
+		}
+		v[idx] = deleteTokens(v[idx], tokens[1:])
+	}
+
+	return cur
+}
@@ -0,0 +1,96 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	doc, err := Decode([]byte(`{"id":"abc","items":[{"n":1},{"n":2}],"a/b":{"c~d":true}}`))
+	require.NoError(t, err)
+
+	testcases := []struct {
+		desc    string
+		pointer string
+		want    any
+		wantErr bool
+	}{
+		{desc: "whole document", pointer: "", want: doc},
+		{desc: "object key", pointer: "/id", want: "abc"},
+		{desc: "array index", pointer: "/items/1/n", want: json.Number("2")},
+		{desc: "escaped tokens", pointer: "/a~1b/c~0d", want: true},
+		{desc: "missing key", pointer: "/foo", wantErr: true},
+		{desc: "index out of range", pointer: "/items/2", wantErr: true},
+		{desc: "token on scalar", pointer: "/id/0", wantErr: true},
+		{desc: "malformed", pointer: "id", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Get(doc, tc.pointer)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode([]byte(" {\"id\":1}\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]any{"id": json.Number("1")}, doc)
+	}
+
+	for _, body := range []string{
+		`{"id":1}{"oops":true} garbage`,
+		`{"id":1}<html>`,
+		`1 2`,
+	} {
+		_, err := Decode([]byte(body))
+		assert.Error(t, err, body)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	assert.Equal(t, "", Join())
+	assert.Equal(t, "/a~1b/c~0d/0", Join("a/b", "c~d", "0"))
+}
+
+func TestDelete(t *testing.T) {
+	testcases := []struct {
+		desc    string
+		doc     string
+		pointer string
+		want    string
+	}{
+		{desc: "object key", doc: `{"a":1,"b":2}`, pointer: "/a", want: `{"b":2}`},
+		{desc: "nested key", doc: `{"a":{"b":1,"c":2}}`, pointer: "/a/b", want: `{"a":{"c":2}}`},
+		{desc: "array index", doc: `[1,2,3]`, pointer: "/1", want: `[1,3]`},
+		{desc: "wildcard index", doc: `[{"id":1,"n":"a"},{"id":2,"n":"b"}]`, pointer: "/*/id", want: `[{"n":"a"},{"n":"b"}]`},
+		{desc: "wildcard key", doc: `{"a":{"id":1},"b":{"id":2}}`, pointer: "/*/id", want: `{"a":{},"b":{}}`},
+		{desc: "missing", doc: `{"a":1}`, pointer: "/b/c", want: `{"a":1}`},
+		{desc: "whole document", doc: `{"a":1}`, pointer: "", want: `null`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			doc, err := Decode([]byte(tc.doc))
+			require.NoError(t, err)
+
+			want, err := Decode([]byte(tc.want))
+			require.NoError(t, err)
+
+			got, err := Delete(doc, tc.pointer)
+			if assert.NoError(t, err) {
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
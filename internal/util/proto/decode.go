@@ -2,49 +2,161 @@ package proto
 
 import (
 	"encoding/binary"
+	"fmt"
+	"hash/crc32"
 	"io"
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 )
 
-const decoderBufSize = 4096
+const (
+	decoderBufSize = 4096
+	// DefaultMaxSize is the largest message decoded by default.
+	DefaultMaxSize = 64 << 20
+)
+
+// ErrTooLarge is matched by errors of messages larger than the limit.
+var ErrTooLarge = errors.New("message too large")
+
+// TooLargeError tells where the oversized message is.
+type TooLargeError struct {
+	// Offset is where the length prefix of the message starts.
+	Offset int64
+	Size   int
+	Max    int
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("message too large at offset %d: %d bytes, max: %d", e.Offset, e.Size, e.Max)
+}
+
+func (e *TooLargeError) Is(target error) bool { return target == ErrTooLarge }
+
+// ErrCorrupt is matched by errors of truncated or damaged messages.
+var ErrCorrupt = errors.New("message corrupt")
+
+// CorruptError tells where the corrupt message is.
+type CorruptError struct {
+	// Offset is where the length prefix of the message starts.
+	Offset int64
+	Reason string
+}
+
+func (e *CorruptError) Error() string {
+	return fmt.Sprintf("corrupt message at offset %d: %s", e.Offset, e.Reason)
+}
+
+func (e *CorruptError) Is(target error) bool { return target == ErrCorrupt }
+
+type DecoderOption func(*Decoder)
+
+// WithMaxSize limits size of a message. Buffer grows up to it.
+// Non-positive size falls back to DefaultMaxSize.
+func WithMaxSize(size int) DecoderOption {
+	return func(d *Decoder) {
+		if size <= 0 {
+			size = DefaultMaxSize
+		}
+		d.maxSize = size
+	}
+}
+
+// WithFormat sets format of messages. The header should be skipped by the caller.
+func WithFormat(format Format) DecoderOption {
+	return func(d *Decoder) { d.format = format }
+}
+
+// WithOffset sets the position of the source in its file,
+// so offsets are reported from the start of the file.
+func WithOffset(offset int64) DecoderOption {
+	return func(d *Decoder) { d.offset = offset }
+}
 
 type Decoder struct {
 	src    io.Reader
 	lenbuf []byte
-	buf    []byte
+	// buf is reused across messages. It only grows.
+	buf []byte
+
+	maxSize int
+	format  Format
+	// offset is the position in the source.
+	offset int64
 }
 
-func NewDecoder(r io.Reader) *Decoder {
-	return &Decoder{
-		src:    r,
-		lenbuf: make([]byte, 4),
-		buf:    make([]byte, decoderBufSize),
+func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
+	d := &Decoder{
+		src:     r,
+		maxSize: DefaultMaxSize,
+	}
+
+	for _, opt := range opts {
+		opt(d)
 	}
+
+	// Framed messages have checksum after the length.
+	d.lenbuf = make([]byte, 4)
+	if d.format == FormatFramed {
+		d.lenbuf = make([]byte, 8)
+	}
+
+	d.buf = make([]byte, min(decoderBufSize, d.maxSize))
+
+	return d
 }
 
 // Decode decodes bytes read from source info proto.Message.
 // It is caller's responsibility to handle EOF.
+// Truncated or damaged messages are reported as CorruptError.
+// Decoding can continue after damaged ones, since they are consumed.
 func (d *Decoder) Decode(m proto.Message) error {
-	if _, err := io.ReadFull(d.src, d.lenbuf); err != nil {
-		return errors.Wrap(err, "reading length")
+	start := d.offset
+
+	n, err := io.ReadFull(d.src, d.lenbuf)
+	d.offset += int64(n)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return &CorruptError{Offset: start, Reason: "truncated length"}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "reading length at offset %d", start)
 	}
 
-	size := binary.LittleEndian.Uint32(d.lenbuf)
+	size := int(binary.LittleEndian.Uint32(d.lenbuf))
 
-	if size > decoderBufSize {
-		return io.ErrShortBuffer
+	if size > d.maxSize {
+		return &TooLargeError{Offset: start, Size: size, Max: d.maxSize}
 	}
 
-	_, err := io.ReadFull(d.src, d.buf[:size])
+	if size > len(d.buf) {
+		// Grow geometrically, so growing to a large message doesn't take many steps.
+		d.buf = make([]byte, min(max(size, 2*len(d.buf)), d.maxSize))
+	}
+
+	n, err = io.ReadFull(d.src, d.buf[:size])
+	d.offset += int64(n)
+	if errors.Is(err, io.ErrUnexpectedEOF) || (size > 0 && errors.Is(err, io.EOF)) {
+		return &CorruptError{Offset: start, Reason: "truncated message"}
+	}
 	if err != nil {
-		return errors.Wrap(err, "reading message")
+		return errors.Wrapf(err, "reading message at offset %d", start)
+	}
+
+	if d.format == FormatFramed {
+		expected := binary.LittleEndian.Uint32(d.lenbuf[4:])
+		if actual := crc32.Checksum(d.buf[:size], crcTable); actual != expected {
+			return &CorruptError{Offset: start, Reason: fmt.Sprintf("checksum mismatch: %08x, expected: %08x", actual, expected)}
+		}
 	}
 
 	if err := proto.Unmarshal(d.buf[:size], m); err != nil {
-		return errors.Wrap(err, "unmarshaling message")
+		return &CorruptError{Offset: start, Reason: fmt.Sprintf("unmarshaling: %v", err)}
 	}
 
 	return nil
 }
+
+// Offset returns the position in the source.
+func (d *Decoder) Offset() int64 {
+	return d.offset
+}
@@ -2,6 +2,7 @@ package proto
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/oneee-playground/r2d2-tester/internal/work"
@@ -51,3 +52,130 @@ func TestDecode(t *testing.T) {
 		assert.Equal(t, expected.Body, dst.Body)
 	}
 }
+
+func TestDecodeLarge(t *testing.T) {
+	small := &work.Input{Method: "GET", Path: "/small"}
+	large := &work.Input{Method: "POST", Path: "/bulk", Body: bytes.Repeat([]byte("a"), 3*decoderBufSize)}
+
+	buf := bytes.NewBuffer(nil)
+	for _, m := range []*work.Input{small, large, small, large} {
+		b, err := MarshalWithSize(m)
+		require.NoError(t, err)
+		buf.Write(b)
+	}
+
+	dec := NewDecoder(buf)
+
+	for _, expected := range []*work.Input{small, large, small, large} {
+		dst := new(work.Input)
+		require.NoError(t, dec.Decode(dst))
+
+		assert.Equal(t, expected.Path, dst.Path)
+		assert.Equal(t, expected.Body, dst.Body)
+	}
+
+	// Buffer grown once is reused.
+	assert.Less(t, len(dec.buf), 4*decoderBufSize)
+}
+
+func TestDecodeTooLarge(t *testing.T) {
+	small := &work.Input{Method: "GET", Path: "/small"}
+	large := &work.Input{Method: "POST", Path: "/bulk", Body: bytes.Repeat([]byte("a"), 2*decoderBufSize)}
+
+	first, err := MarshalWithSize(small)
+	require.NoError(t, err)
+	second, err := MarshalWithSize(large)
+	require.NoError(t, err)
+
+	dec := NewDecoder(bytes.NewReader(append(first, second...)), WithMaxSize(decoderBufSize))
+
+	require.NoError(t, dec.Decode(new(work.Input)))
+	assert.Equal(t, int64(len(first)), dec.Offset())
+
+	err = dec.Decode(new(work.Input))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	var tooLarge *TooLargeError
+	if assert.ErrorAs(t, err, &tooLarge) {
+		assert.Equal(t, int64(len(first)), tooLarge.Offset)
+		assert.Equal(t, len(second)-4, tooLarge.Size)
+		assert.Equal(t, decoderBufSize, tooLarge.Max)
+	}
+}
+
+func TestDecodeFramed(t *testing.T) {
+	data := &work.Input{Method: "POST", Path: "/foo", Body: []byte("bar")}
+
+	record, err := MarshalFramed(data)
+	require.NoError(t, err)
+
+	file := append(Header(), record...)
+
+	// Flip a byte of the second message.
+	damaged := append([]byte(nil), record...)
+	damaged[len(damaged)-1] ^= 0xff
+	file = append(file, damaged...)
+	file = append(file, record...)
+	// Truncated one at the end.
+	file = append(file, record[:len(record)-2]...)
+
+	format, err := ReadFormat(bytes.NewReader(file))
+	require.NoError(t, err)
+	require.Equal(t, FormatFramed, format)
+
+	dec := NewDecoder(bytes.NewReader(file[HeaderSize:]), WithFormat(format), WithOffset(HeaderSize))
+
+	dst := new(work.Input)
+	require.NoError(t, dec.Decode(dst))
+	assert.Equal(t, data.Body, dst.Body)
+
+	var corrupt *CorruptError
+
+	err = dec.Decode(new(work.Input))
+	assert.ErrorIs(t, err, ErrCorrupt)
+	if assert.ErrorAs(t, err, &corrupt) {
+		assert.Equal(t, int64(HeaderSize+len(record)), corrupt.Offset)
+	}
+
+	// Damaged one is skipped.
+	require.NoError(t, dec.Decode(dst))
+	assert.Equal(t, data.Body, dst.Body)
+
+	err = dec.Decode(new(work.Input))
+	if assert.ErrorAs(t, err, &corrupt) {
+		assert.Equal(t, int64(HeaderSize+3*len(record)), corrupt.Offset)
+	}
+
+	assert.ErrorIs(t, dec.Decode(new(work.Input)), io.EOF)
+}
+
+func TestReadFormat(t *testing.T) {
+	plain, _, err := createTestData(1)
+	require.NoError(t, err)
+
+	format, err := ReadFormat(bytes.NewReader(plain.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPlain, format)
+
+	format, err = ReadFormat(bytes.NewReader(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPlain, format)
+
+	_, err = ReadFormat(bytes.NewReader(Header()[:6]))
+	assert.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestDecodeNonPositiveMaxSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		testdata, expected, err := createTestData(1)
+		require.NoError(t, err)
+
+		dec := NewDecoder(testdata, WithMaxSize(size))
+		assert.Equal(t, DefaultMaxSize, dec.maxSize)
+
+		dst := new(work.Input)
+		if assert.NoError(t, dec.Decode(dst)) {
+			assert.Equal(t, expected.Body, dst.Body)
+		}
+	}
+}
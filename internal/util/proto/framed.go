@@ -0,0 +1,94 @@
+package proto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format is how messages are laid out in a file.
+type Format int
+
+const (
+	// FormatPlain is a sequence of size prefixed messages. It has no header.
+	FormatPlain Format = iota
+	// FormatFramed starts with a header, and each message is prefixed with its size and checksum.
+	FormatFramed
+)
+
+const (
+	framedMagic   = "R2D2"
+	FramedVersion = 1
+
+	// HeaderSize is the size of framed file header: magic and version (32bit).
+	HeaderSize = 8
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Header returns the header of a framed file.
+func Header() []byte {
+	b := make([]byte, HeaderSize)
+	copy(b, framedMagic)
+	binary.LittleEndian.PutUint32(b[4:], FramedVersion)
+	return b
+}
+
+// ReadFormat tells format of the file from its header.
+// Files without the header, including empty ones, are FormatPlain.
+func ReadFormat(r io.ReaderAt) (Format, error) {
+	b := make([]byte, HeaderSize)
+
+	n, err := r.ReadAt(b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return FormatPlain, errors.Wrap(err, "reading header")
+	}
+
+	if n < len(framedMagic) || !bytes.Equal(b[:len(framedMagic)], []byte(framedMagic)) {
+		return FormatPlain, nil
+	}
+
+	if n < HeaderSize {
+		return FormatFramed, &CorruptError{Offset: 0, Reason: "truncated header"}
+	}
+
+	if version := binary.LittleEndian.Uint32(b[4:]); version != FramedVersion {
+		return FormatFramed, errors.Errorf("unsupported framed version: %d", version)
+	}
+
+	return FormatFramed, nil
+}
+
+// Start returns offset of the first message.
+func (f Format) Start() int64 {
+	if f == FormatFramed {
+		return HeaderSize
+	}
+	return 0
+}
+
+// Marshal marshals the message as a record of the format.
+func (f Format) Marshal(m proto.Message) ([]byte, error) {
+	if f == FormatFramed {
+		return MarshalFramed(m)
+	}
+	return MarshalWithSize(m)
+}
+
+// MarshalFramed is like MarshalWithSize, but adds CRC-32C of the message after the size.
+func MarshalFramed(m proto.Message) ([]byte, error) {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, 8)
+	binary.LittleEndian.PutUint32(prefix[:4], uint32(len(b)))
+	binary.LittleEndian.PutUint32(prefix[4:], crc32.Checksum(b, crcTable))
+
+	return append(prefix, b...), nil
+}
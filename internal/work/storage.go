@@ -2,11 +2,26 @@ package work
 
 import (
 	"context"
+	"io"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
+var ErrNotFound = errors.New("work not found")
+
+// ErrCorrupt is matched by errors of damaged storage.
+// It's not the submission's fault.
+var ErrCorrupt = errors.New("work storage corrupt")
+
 type Storage interface {
 	FetchTemplates(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (templates map[uuid.UUID]*Template, err error)
 	Stream(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (stream <-chan *Work, errchan <-chan error)
+	// StreamFrom is like Stream, but skips the first offset works.
+	StreamFrom(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, offset int) (stream <-chan *Work, errchan <-chan error)
+	// Count returns the number of works in the section.
+	Count(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (int, error)
+	// Get returns the work with the id. It returns ErrNotFound if there is no such work.
+	Get(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, workID uuid.UUID) (*Work, error)
+	Fixture(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, name string) (io.ReadCloser, error)
 }
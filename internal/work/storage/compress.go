@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Compression of work and template files.
+// Compressed files are read only. Index keeps working,
+// since its offsets are of decompressed content.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+var ErrCompressed = errors.New("compressed file can't be appended")
+
+var compressions = []struct {
+	compression Compression
+	ext         string
+	magic       []byte
+}{
+	{compression: CompressionZstd, ext: ".zst", magic: []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{compression: CompressionGzip, ext: ".gz", magic: []byte{0x1f, 0x8b}},
+}
+
+// ParseCompression parses name of compression. Empty name is CompressionNone.
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(name); c {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return c, nil
+	default:
+		return CompressionNone, errors.Errorf("unknown compression: %s", name)
+	}
+}
+
+func (c Compression) ext() string {
+	for _, known := range compressions {
+		if known.compression == c {
+			return known.ext
+		}
+	}
+	return ""
+}
+
+// resolvePath finds the file of path, which may be compressed.
+// File at path is detected by its header. Ones with extension are tried next.
+func resolvePath(path string) (string, Compression, error) {
+	c, err := sniffCompression(path)
+	if err == nil {
+		return path, c, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", CompressionNone, err
+	}
+
+	for _, known := range compressions {
+		if _, statErr := os.Stat(path + known.ext); statErr == nil {
+			return path + known.ext, known.compression, nil
+		}
+	}
+
+	return "", CompressionNone, err
+}
+
+func sniffCompression(path string) (Compression, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return CompressionNone, err
+	}
+	defer file.Close()
+
+	b := make([]byte, 4)
+
+	n, err := file.ReadAt(b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return CompressionNone, errors.Wrap(err, "reading header")
+	}
+
+	for _, known := range compressions {
+		if bytes.HasPrefix(b[:n], known.magic) {
+			return known.compression, nil
+		}
+	}
+
+	return CompressionNone, nil
+}
+
+// isDecompressionError tells err is from damaged compressed content.
+func isDecompressionError(err error) bool {
+	var flateErr flate.CorruptInputError
+	return errors.As(err, &flateErr) ||
+		errors.Is(err, gzip.ErrChecksum) || errors.Is(err, gzip.ErrHeader) ||
+		errors.Is(err, zstd.ErrCRCMismatch) || errors.Is(err, zstd.ErrMagicMismatch)
+}
+
+// decompressed reads decompressed content of the file, and closes both.
+type decompressed struct {
+	io.Reader
+	close func()
+	file  *os.File
+}
+
+func (d *decompressed) Close() error {
+	d.close()
+	return d.file.Close()
+}
+
+func decompress(file *os.File, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bufio.NewReader(file))
+		if err != nil {
+			return nil, errors.Wrap(err, "reading gzip header")
+		}
+		return &decompressed{Reader: r, close: func() { r.Close() }, file: file}, nil
+	case CompressionZstd:
+		r, err := zstd.NewReader(bufio.NewReader(file), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrap(err, "creating zstd reader")
+		}
+		return &decompressed{Reader: r, close: r.Close, file: file}, nil
+	default:
+		return file, nil
+	}
+}
+
+// Compress rewrites work and template files of the section with compression.
+// Files already compressed are left as they are.
+func (s *FSStorage) Compress(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, c Compression) error {
+	if c == CompressionNone {
+		return nil
+	}
+
+	for _, path := range []string{s.workPath(taskID, sectionID), s.templatePath(taskID, sectionID)} {
+		if err := compressFile(path, c); err != nil {
+			return errors.Wrapf(err, "compressing %s", path)
+		}
+	}
+
+	return nil
+}
+
+func compressFile(path string, c Compression) error {
+	current, err := sniffCompression(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current != CompressionNone {
+		return nil
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "opening file")
+	}
+	defer src.Close()
+
+	dstPath := path + c.ext()
+	tmpPath := dstPath + ".tmp"
+
+	dst, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Wrap(err, "creating file")
+	}
+	defer os.Remove(tmpPath)
+	defer dst.Close()
+
+	var w io.WriteCloser
+	switch c {
+	case CompressionGzip:
+		w = gzip.NewWriter(dst)
+	case CompressionZstd:
+		if w, err = zstd.NewWriter(dst, zstd.WithEncoderConcurrency(1)); err != nil {
+			return errors.Wrap(err, "creating zstd writer")
+		}
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return errors.Wrap(err, "compressing")
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "compressing")
+	}
+	if err := dst.Close(); err != nil {
+		return errors.Wrap(err, "closing file")
+	}
+
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		return errors.Wrap(err, "replacing file")
+	}
+
+	return os.Remove(path)
+}
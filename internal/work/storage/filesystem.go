@@ -2,10 +2,12 @@ package storage
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 	protofmt "github.com/oneee-playground/r2d2-tester/internal/util/proto"
@@ -21,24 +23,147 @@ const (
 
 type FSStorage struct {
 	root string
+
+	maxMessageSize int
+	// format is used to create files.
+	format protofmt.Format
 }
 
 var _ work.Storage = (*FSStorage)(nil)
 
-func NewFSStorage(root string) *FSStorage {
-	return &FSStorage{root: root}
+type Option func(*FSStorage)
+
+// WithMaxMessageSize limits size of a stored work or template.
+// Larger ones fail to be read. Non-positive size falls back to the default.
+func WithMaxMessageSize(size int) Option {
+	return func(s *FSStorage) {
+		if size <= 0 {
+			size = protofmt.DefaultMaxSize
+		}
+		s.maxMessageSize = size
+	}
 }
 
-func (s *FSStorage) FetchTemplates(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (templates map[uuid.UUID]*work.Template, err error) {
-	path := filepath.Join(s.root, taskID.String(), sectionID.String(), _filepathTemplatePrefix)
+// WithFramed makes new files be written in framed format, which has checksums.
+// Existing files are appended in their own format.
+func WithFramed() Option {
+	return func(s *FSStorage) { s.format = protofmt.FormatFramed }
+}
+
+func NewFSStorage(root string, opts ...Option) *FSStorage {
+	s := &FSStorage{
+		root:           root,
+		maxMessageSize: protofmt.DefaultMaxSize,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// newDecoder decodes messages of the format from r, which is positioned at offset.
+func (s *FSStorage) newDecoder(r io.Reader, format protofmt.Format, offset int64) *protofmt.Decoder {
+	return protofmt.NewDecoder(bufio.NewReader(r),
+		protofmt.WithMaxSize(s.maxMessageSize),
+		protofmt.WithFormat(format),
+		protofmt.WithOffset(offset),
+	)
+}
+
+// openRecords opens the file and positions it at offset of decompressed content.
+// Offset zero means the first message, after the header if any.
+func openRecords(path string, offset int64) (io.ReadCloser, protofmt.Format, error) {
+	path, compression, err := resolvePath(path)
+	if err != nil {
+		return nil, protofmt.FormatPlain, err
+	}
 
 	file, err := os.Open(path)
+	if err != nil {
+		return nil, protofmt.FormatPlain, err
+	}
+
+	if compression == CompressionNone {
+		format, err := protofmt.ReadFormat(file)
+		if err != nil {
+			file.Close()
+			return nil, format, corrupted(err)
+		}
+
+		if offset == 0 {
+			offset = format.Start()
+		}
+
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, format, errors.Wrap(err, "seeking")
+		}
+
+		return file, format, nil
+	}
+
+	// Compressed content can't be seeked. It is skipped by reading.
+	r, err := decompress(file, compression)
+	if err != nil {
+		file.Close()
+		return nil, protofmt.FormatPlain, err
+	}
+
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(protofmt.HeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.Close()
+		return nil, protofmt.FormatPlain, errors.Wrap(err, "reading header")
+	}
+
+	format, err := protofmt.ReadFormat(bytes.NewReader(header))
+	if err != nil {
+		r.Close()
+		return nil, format, corrupted(err)
+	}
+
+	if offset == 0 {
+		offset = format.Start()
+	}
+
+	if _, err := io.CopyN(io.Discard, br, offset); err != nil {
+		r.Close()
+		return nil, format, errors.Wrap(err, "skipping")
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{br, r}, format, nil
+}
+
+// corruptError makes damaged data match work.ErrCorrupt.
+type corruptError struct{ err error }
+
+func (e *corruptError) Error() string        { return e.err.Error() }
+func (e *corruptError) Cause() error         { return e.err }
+func (e *corruptError) Unwrap() error        { return e.err }
+func (e *corruptError) Is(target error) bool { return target == work.ErrCorrupt }
+
+// corrupted marks err as work.ErrCorrupt if it is caused by damaged data.
+func corrupted(err error) error {
+	if errors.Is(err, protofmt.ErrCorrupt) || isDecompressionError(err) {
+		return &corruptError{err: err}
+	}
+	return err
+}
+
+func (s *FSStorage) FetchTemplates(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (templates map[uuid.UUID]*work.Template, err error) {
+	file, format, err := openRecords(s.templatePath(taskID, sectionID), 0)
 	if err != nil {
 		return nil, errors.Wrap(err, "opening template path")
 	}
 	defer file.Close()
 
-	dec := protofmt.NewDecoder(bufio.NewReader(file))
+	dec := s.newDecoder(file, format, format.Start())
 
 	templates = make(map[uuid.UUID]*work.Template)
 
@@ -56,7 +181,7 @@ func (s *FSStorage) FetchTemplates(ctx context.Context, taskID uuid.UUID, sectio
 			break
 		}
 		if err != nil {
-			return nil, errors.Wrap(err, "decoding template")
+			return nil, corrupted(errors.Wrap(err, "decoding template"))
 		}
 
 		templateID, err := uuid.FromBytes(dst.Id)
@@ -71,6 +196,12 @@ func (s *FSStorage) FetchTemplates(ctx context.Context, taskID uuid.UUID, sectio
 }
 
 func (s *FSStorage) Stream(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (<-chan *work.Work, <-chan error) {
+	return s.StreamFrom(ctx, taskID, sectionID, 0)
+}
+
+// StreamFrom streams works after skipping the first offset ones.
+// Index is required unless offset is zero.
+func (s *FSStorage) StreamFrom(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, offset int) (<-chan *work.Work, <-chan error) {
 	// TODO: Specify buffer size as proper one.
 	stream := make(chan *work.Work)
 	errchan := make(chan error, 1)
@@ -78,16 +209,35 @@ func (s *FSStorage) Stream(ctx context.Context, taskID uuid.UUID, sectionID uuid
 	go func() {
 		defer close(stream)
 
-		path := filepath.Join(s.root, taskID.String(), sectionID.String(), _filepathWorkPrefix)
+		path := s.workPath(taskID, sectionID)
+
+		var start int64
+		if offset > 0 {
+			entry, err := readIndexEntry(indexPath(path), offset)
+			if errors.Is(err, io.EOF) {
+				// Nothing left after offset.
+				return
+			}
+			if err != nil {
+				errchan <- errors.Wrap(err, "looking up offset")
+				return
+			}
 
-		file, err := os.Open(path)
+			start = entry.offset
+		}
+
+		file, format, err := openRecords(path, start)
 		if err != nil {
 			errchan <- errors.Wrap(err, "opening work path")
 			return
 		}
 		defer file.Close()
 
-		dec := protofmt.NewDecoder(bufio.NewReader(file))
+		if start == 0 {
+			start = format.Start()
+		}
+
+		dec := s.newDecoder(file, format, start)
 		for {
 			dst := new(work.Work)
 
@@ -96,13 +246,13 @@ func (s *FSStorage) Stream(ctx context.Context, taskID uuid.UUID, sectionID uuid
 				break
 			}
 			if err != nil {
-				errchan <- errors.Wrap(err, "decoding work")
+				errchan <- corrupted(errors.Wrap(err, "decoding work"))
 				return
 			}
 
 			select {
 			case <-ctx.Done():
-				errchan <- errors.Wrap(err, "streaming templates")
+				errchan <- errors.Wrap(ctx.Err(), "streaming works")
 				return
 			case stream <- dst:
 			}
@@ -112,36 +262,98 @@ func (s *FSStorage) Stream(ctx context.Context, taskID uuid.UUID, sectionID uuid
 	return stream, errchan
 }
 
+func (s *FSStorage) Fixture(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, name string) (io.ReadCloser, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, errors.Errorf("malformed fixture name: %s", name)
+	}
+
+	path := filepath.Join(s.root, taskID.String(), sectionID.String(), name)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening fixture path")
+	}
+
+	return file, nil
+}
+
+// InsertWork appends the work, and its entry to the index.
+// Index is built first if the file was written without it.
 func (s *FSStorage) InsertWork(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, work *work.Work) error {
-	path := filepath.Join(s.root, taskID.String(), sectionID.String(), _filepathWorkPrefix)
-	return s.insertRaw(path, work)
+	path := s.workPath(taskID, sectionID)
+
+	if err := ensureIndex(path, s.maxMessageSize); err != nil {
+		return err
+	}
+
+	offset, err := s.insertRaw(path, work)
+	if err != nil {
+		return err
+	}
+
+	return appendIndexEntry(indexPath(path), indexEntry{offset: offset, id: indexID(work)})
 }
 
 func (s *FSStorage) InsertTemplate(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, template *work.Template) error {
-	path := filepath.Join(s.root, taskID.String(), sectionID.String(), _filepathTemplatePrefix)
-	return s.insertRaw(path, template)
+	path := s.templatePath(taskID, sectionID)
+
+	_, err := s.insertRaw(path, template)
+	return err
 }
 
-func (s *FSStorage) insertRaw(path string, m proto.Message) error {
+// insertRaw appends the message, and returns the offset it was written at.
+// Compressed files can't be appended.
+func (s *FSStorage) insertRaw(path string, m proto.Message) (int64, error) {
+	if resolved, compression, err := resolvePath(path); err == nil && compression != CompressionNone {
+		return 0, errors.Wrap(ErrCompressed, resolved)
+	}
+
 	if err := os.MkdirAll(filepath.Dir(path), 0744); err != nil {
-		return errors.Wrap(err, "mkdir all")
+		return 0, errors.Wrap(err, "mkdir all")
 	}
 
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
 	if err != nil {
-		return errors.Wrap(err, "opening file")
+		return 0, errors.Wrap(err, "opening file")
 	}
 	defer file.Close()
 
-	b, err := protofmt.MarshalWithSize(m)
+	info, err := file.Stat()
 	if err != nil {
-		return errors.Wrap(err, "marshaling work")
+		return 0, errors.Wrap(err, "stat file")
+	}
+
+	offset := info.Size()
+
+	format := s.format
+	if offset > 0 {
+		if format, err = protofmt.ReadFormat(file); err != nil {
+			return 0, corrupted(err)
+		}
+	} else if format == protofmt.FormatFramed {
+		if _, err := file.Write(protofmt.Header()); err != nil {
+			return 0, errors.Wrap(err, "writing header")
+		}
+		offset = protofmt.HeaderSize
+	}
+
+	b, err := format.Marshal(m)
+	if err != nil {
+		return 0, errors.Wrap(err, "marshaling work")
 	}
 
 	_, err = file.Write(b)
 	if err != nil {
-		return errors.Wrap(err, "writing to file")
+		return 0, errors.Wrap(err, "writing to file")
 	}
 
-	return nil
+	return offset, nil
+}
+
+func (s *FSStorage) workPath(taskID, sectionID uuid.UUID) string {
+	return filepath.Join(s.root, taskID.String(), sectionID.String(), _filepathWorkPrefix)
+}
+
+func (s *FSStorage) templatePath(taskID, sectionID uuid.UUID) string {
+	return filepath.Join(s.root, taskID.String(), sectionID.String(), _filepathTemplatePrefix)
 }
@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -148,3 +150,315 @@ loop:
 
 	s.Equal(cnt, 0)
 }
+
+func (s *FSStorageSuite) TestStreamMaxMessageSize() {
+	defer goleak.VerifyNone(s.T())
+
+	large := &work.Work{
+		Id:    uuid.Nil[:],
+		Input: &work.Input{Method: "POST", Path: "/bulk", Body: bytes.Repeat([]byte("a"), 8192)},
+	}
+
+	s.Require().NoError(s.storage.InsertWork(context.Background(), uuid.Nil, uuid.Nil, large))
+
+	stream, errchan := s.storage.Stream(context.Background(), uuid.Nil, uuid.Nil)
+
+	got, ok := <-stream
+	if s.True(ok) {
+		s.Equal(large.Input.Body, got.Input.Body)
+	}
+	_, ok = <-stream
+	s.False(ok)
+
+	limited := NewFSStorage(s.base, WithMaxMessageSize(4096))
+
+	stream, errchan = limited.Stream(context.Background(), uuid.Nil, uuid.Nil)
+
+	_, ok = <-stream
+	s.False(ok)
+	s.ErrorIs(<-errchan, proto.ErrTooLarge)
+}
+
+func (s *FSStorageSuite) TestFixture() {
+	dir := filepath.Join(s.base, uuid.Nil.String(), uuid.Nil.String())
+	s.Require().NoError(os.MkdirAll(dir, 0744))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "seed.sql"), []byte("SELECT 1;"), 0644))
+
+	r, err := s.storage.Fixture(context.Background(), uuid.Nil, uuid.Nil, "seed.sql")
+	if !s.NoError(err) {
+		return
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	s.NoError(err)
+	s.Equal("SELECT 1;", string(b))
+
+	_, err = s.storage.Fixture(context.Background(), uuid.Nil, uuid.Nil, "../seed.sql")
+	s.Error(err)
+
+	_, err = s.storage.Fixture(context.Background(), uuid.Nil, uuid.Nil, "missing.sql")
+	s.Error(err)
+}
+
+func (s *FSStorageSuite) insertWorks(cnt int) []uuid.UUID {
+	ids := make([]uuid.UUID, cnt)
+	for i := range ids {
+		ids[i] = uuid.New()
+
+		w := &work.Work{
+			Id:    ids[i][:],
+			Input: &work.Input{Method: "GET", Path: "/" + ids[i].String()},
+		}
+		s.Require().NoError(s.storage.InsertWork(context.Background(), uuid.Nil, uuid.Nil, w))
+	}
+	return ids
+}
+
+func (s *FSStorageSuite) TestCount() {
+	_, err := s.storage.Count(context.Background(), uuid.Nil, uuid.Nil)
+	s.ErrorIs(err, ErrNoIndex)
+
+	s.insertWorks(5)
+
+	n, err := s.storage.Count(context.Background(), uuid.Nil, uuid.Nil)
+	s.NoError(err)
+	s.Equal(5, n)
+}
+
+func (s *FSStorageSuite) TestStreamFrom() {
+	defer goleak.VerifyNone(s.T())
+
+	ids := s.insertWorks(5)
+
+	stream, errchan := s.storage.StreamFrom(context.Background(), uuid.Nil, uuid.Nil, 3)
+
+	var got []uuid.UUID
+	for w := range stream {
+		got = append(got, uuid.UUID(w.Id))
+	}
+	s.Len(errchan, 0)
+	s.Equal(ids[3:], got)
+
+	stream, errchan = s.storage.StreamFrom(context.Background(), uuid.Nil, uuid.Nil, 5)
+
+	_, ok := <-stream
+	s.False(ok)
+	s.Len(errchan, 0)
+}
+
+func (s *FSStorageSuite) TestGet() {
+	ids := s.insertWorks(3)
+
+	w, err := s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, ids[1])
+	if s.NoError(err) {
+		s.Equal(ids[1][:], w.Id)
+		s.Equal("/"+ids[1].String(), w.Input.Path)
+	}
+
+	_, err = s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, uuid.New())
+	s.ErrorIs(err, work.ErrNotFound)
+}
+
+func (s *FSStorageSuite) TestRebuildIndex() {
+	ids := s.insertWorks(3)
+
+	// Works written before index existed.
+	path := s.storage.workPath(uuid.Nil, uuid.Nil)
+	s.Require().NoError(os.Remove(indexPath(path)))
+
+	n, err := s.storage.RebuildIndex(context.Background(), uuid.Nil, uuid.Nil)
+	s.NoError(err)
+	s.Equal(3, n)
+
+	w, err := s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, ids[2])
+	if s.NoError(err) {
+		s.Equal(ids[2][:], w.Id)
+	}
+
+	// Legacy file gets indexed on the next insert.
+	s.Require().NoError(os.Remove(indexPath(path)))
+	s.insertWorks(1)
+
+	n, err = s.storage.Count(context.Background(), uuid.Nil, uuid.Nil)
+	s.NoError(err)
+	s.Equal(4, n)
+}
+
+func (s *FSStorageSuite) TestStaleIndex() {
+	s.insertWorks(3)
+
+	// Appending the entry failed after the last work was written.
+	path := s.storage.workPath(uuid.Nil, uuid.Nil)
+	info, err := os.Stat(indexPath(path))
+	s.Require().NoError(err)
+	s.Require().NoError(os.Truncate(indexPath(path), info.Size()-indexEntrySize))
+
+	ids := s.insertWorks(1)
+
+	n, err := s.storage.Count(context.Background(), uuid.Nil, uuid.Nil)
+	s.NoError(err)
+	s.Equal(4, n)
+
+	w, err := s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, ids[0])
+	if s.NoError(err) {
+		s.Equal(ids[0][:], w.Id)
+	}
+
+	info, err = os.Stat(path)
+	s.Require().NoError(err)
+
+	fresh, err := indexFresh(path, info.Size(), proto.DefaultMaxSize)
+	s.NoError(err)
+	s.True(fresh)
+
+	// The only work is at offset zero.
+	s.Require().NoError(os.Remove(path))
+	s.Require().NoError(os.Remove(indexPath(path)))
+	s.insertWorks(1)
+
+	info, err = os.Stat(path)
+	s.Require().NoError(err)
+
+	fresh, err = indexFresh(path, info.Size(), proto.DefaultMaxSize)
+	s.NoError(err)
+	s.True(fresh)
+}
+
+func (s *FSStorageSuite) TestFramed() {
+	defer goleak.VerifyNone(s.T())
+
+	s.storage = NewFSStorage(s.base, WithFramed())
+	ids := s.insertWorks(3)
+
+	path := s.storage.workPath(uuid.Nil, uuid.Nil)
+
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	format, err := proto.ReadFormat(file)
+	file.Close()
+	s.Require().NoError(err)
+	s.Equal(proto.FormatFramed, format)
+
+	stream, errchan := s.storage.Stream(context.Background(), uuid.Nil, uuid.Nil)
+
+	var got []uuid.UUID
+	for w := range stream {
+		got = append(got, uuid.UUID(w.Id))
+	}
+	s.Len(errchan, 0)
+	s.Equal(ids, got)
+
+	w, err := s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, ids[0])
+	if s.NoError(err) {
+		s.Equal(ids[0][:], w.Id)
+	}
+
+	// Plain storage appends in the file's format.
+	plain := NewFSStorage(s.base)
+	s.Require().NoError(plain.InsertWork(context.Background(), uuid.Nil, uuid.Nil, &work.Work{Id: uuid.Nil[:]}))
+
+	corruptions, err := plain.Verify(context.Background(), uuid.Nil, uuid.Nil)
+	s.NoError(err)
+	s.Empty(corruptions)
+}
+
+func (s *FSStorageSuite) TestVerify() {
+	defer goleak.VerifyNone(s.T())
+
+	s.storage = NewFSStorage(s.base, WithFramed())
+	s.insertWorks(3)
+
+	path := s.storage.workPath(uuid.Nil, uuid.Nil)
+
+	second, err := readIndexEntry(indexPath(path), 1)
+	s.Require().NoError(err)
+
+	b, err := os.ReadFile(path)
+	s.Require().NoError(err)
+
+	// Damage the body of the second work, and cut the last one.
+	b[second.offset+10] ^= 0xff
+	s.Require().NoError(os.WriteFile(path, b[:len(b)-3], 0644))
+
+	corruptions, err := s.storage.Verify(context.Background(), uuid.Nil, uuid.Nil)
+	s.Require().NoError(err)
+	if s.Len(corruptions, 2) {
+		s.Equal(_filepathWorkPrefix, corruptions[0].File)
+		s.Equal(second.offset, corruptions[0].Offset)
+		s.Contains(corruptions[0].Reason, "checksum")
+		s.Contains(corruptions[1].Reason, "truncated")
+	}
+
+	stream, errchan := s.storage.Stream(context.Background(), uuid.Nil, uuid.Nil)
+	for range stream {
+	}
+	s.ErrorIs(<-errchan, work.ErrCorrupt)
+}
+
+func (s *FSStorageSuite) TestCompress() {
+	defer goleak.VerifyNone(s.T())
+
+	for _, c := range []Compression{CompressionGzip, CompressionZstd} {
+		s.Run(string(c), func() {
+			s.storage = NewFSStorage(s.T().TempDir(), WithFramed())
+
+			ids := s.insertWorks(5)
+			s.Require().NoError(s.storage.InsertTemplate(context.Background(), uuid.Nil, uuid.Nil, &work.Template{Id: uuid.Nil[:]}))
+
+			s.Require().NoError(s.storage.Compress(context.Background(), uuid.Nil, uuid.Nil, c))
+
+			path := s.storage.workPath(uuid.Nil, uuid.Nil)
+			s.NoFileExists(path)
+			s.FileExists(path + c.ext())
+
+			stream, errchan := s.storage.Stream(context.Background(), uuid.Nil, uuid.Nil)
+
+			var got []uuid.UUID
+			for w := range stream {
+				got = append(got, uuid.UUID(w.Id))
+			}
+			s.Len(errchan, 0)
+			s.Equal(ids, got)
+
+			// Index made before compression stays valid.
+			stream, _ = s.storage.StreamFrom(context.Background(), uuid.Nil, uuid.Nil, 3)
+
+			got = nil
+			for w := range stream {
+				got = append(got, uuid.UUID(w.Id))
+			}
+			s.Equal(ids[3:], got)
+
+			w, err := s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, ids[2])
+			if s.NoError(err) {
+				s.Equal(ids[2][:], w.Id)
+			}
+
+			templates, err := s.storage.FetchTemplates(context.Background(), uuid.Nil, uuid.Nil)
+			s.NoError(err)
+			s.Len(templates, 1)
+
+			err = s.storage.InsertWork(context.Background(), uuid.Nil, uuid.Nil, &work.Work{Id: uuid.Nil[:]})
+			s.ErrorIs(err, ErrCompressed)
+		})
+	}
+}
+
+func (s *FSStorageSuite) TestCompressedWithoutExtension() {
+	ids := s.insertWorks(2)
+	s.Require().NoError(s.storage.Compress(context.Background(), uuid.Nil, uuid.Nil, CompressionGzip))
+
+	// Detected by header.
+	path := s.storage.workPath(uuid.Nil, uuid.Nil)
+	s.Require().NoError(os.Rename(path+".gz", path))
+
+	n, err := s.storage.RebuildIndex(context.Background(), uuid.Nil, uuid.Nil)
+	s.NoError(err)
+	s.Equal(2, n)
+
+	w, err := s.storage.Get(context.Background(), uuid.Nil, uuid.Nil, ids[1])
+	if s.NoError(err) {
+		s.Equal(ids[1][:], w.Id)
+	}
+}
@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	protofmt "github.com/oneee-playground/r2d2-tester/internal/util/proto"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+)
+
+// Index is a sidecar of work file. It has fixed size entries,
+// each with offset (64bit) and id (16 bytes) of a record in order.
+const (
+	_filepathIndexSuffix = ".idx"
+	indexEntrySize       = 8 + 16
+)
+
+var ErrNoIndex = errors.New("index not found")
+
+type indexEntry struct {
+	offset int64
+	// id is nil for works without uuid.
+	id uuid.UUID
+}
+
+func (e indexEntry) marshal() []byte {
+	b := make([]byte, indexEntrySize)
+	binary.LittleEndian.PutUint64(b[:8], uint64(e.offset))
+	copy(b[8:], e.id[:])
+	return b
+}
+
+func unmarshalIndexEntry(b []byte) indexEntry {
+	var e indexEntry
+	e.offset = int64(binary.LittleEndian.Uint64(b[:8]))
+	copy(e.id[:], b[8:])
+	return e
+}
+
+func indexPath(workPath string) string {
+	return workPath + _filepathIndexSuffix
+}
+
+func indexID(w *work.Work) uuid.UUID {
+	id, err := uuid.FromBytes(w.Id)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// readIndexEntry reads entry at ordinal. It returns io.EOF if ordinal is out of range.
+func readIndexEntry(path string, ordinal int) (indexEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return indexEntry{}, ErrNoIndex
+		}
+		return indexEntry{}, errors.Wrap(err, "opening index")
+	}
+	defer file.Close()
+
+	b := make([]byte, indexEntrySize)
+	if _, err := file.ReadAt(b, int64(ordinal)*indexEntrySize); err != nil {
+		if errors.Is(err, io.EOF) {
+			return indexEntry{}, io.EOF
+		}
+		return indexEntry{}, errors.Wrap(err, "reading index")
+	}
+
+	return unmarshalIndexEntry(b), nil
+}
+
+func appendIndexEntry(path string, e indexEntry) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "opening index")
+	}
+	defer file.Close()
+
+	if _, err := file.Write(e.marshal()); err != nil {
+		return errors.Wrap(err, "writing index")
+	}
+
+	return nil
+}
+
+// ensureIndex builds index if the work file exists without it, or if the index is stale.
+// Index gets stale when appending its entry fails after the work was written.
+func ensureIndex(path string, maxMessageSize int) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return errors.Wrap(err, "stat work")
+	}
+
+	if info.Size() == 0 {
+		return nil
+	}
+
+	fresh, err := indexFresh(path, info.Size(), maxMessageSize)
+	if err != nil || fresh {
+		return err
+	}
+
+	_, err = buildIndex(path, maxMessageSize)
+	return err
+}
+
+// indexFresh tells the index covers every record of the work file.
+// Its last entry must point to the record ending at the end of the file.
+func indexFresh(path string, size int64, maxMessageSize int) (bool, error) {
+	info, err := os.Stat(indexPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "stat index")
+	}
+
+	if info.Size()%indexEntrySize != 0 {
+		// Entry was written partially.
+		return false, nil
+	}
+
+	entries := int(info.Size() / indexEntrySize)
+
+	var offset int64
+	if entries > 0 {
+		last, err := readIndexEntry(indexPath(path), entries-1)
+		if err != nil {
+			return false, err
+		}
+		offset = last.offset
+	}
+
+	file, format, err := openRecords(path, offset)
+	if err != nil {
+		return false, errors.Wrap(err, "opening work path")
+	}
+	defer file.Close()
+
+	if entries == 0 {
+		return size == format.Start(), nil
+	}
+	if offset == 0 {
+		offset = format.Start()
+	}
+
+	dec := protofmt.NewDecoder(bufio.NewReader(file),
+		protofmt.WithMaxSize(maxMessageSize),
+		protofmt.WithFormat(format),
+		protofmt.WithOffset(offset),
+	)
+	if err := dec.Decode(new(work.Work)); err != nil {
+		// Entry doesn't point to a record.
+		return false, nil
+	}
+
+	return dec.Offset() == size, nil
+}
+
+// buildIndex scans the work file and replaces its index.
+// It returns the number of works.
+func buildIndex(path string, maxMessageSize int) (int, error) {
+	file, format, err := openRecords(path, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "opening work path")
+	}
+	defer file.Close()
+
+	tmpPath := indexPath(path) + ".tmp"
+
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "creating index")
+	}
+	defer os.Remove(tmpPath)
+	defer tmp.Close()
+
+	w := bufio.NewWriter(tmp)
+	dec := protofmt.NewDecoder(bufio.NewReader(file),
+		protofmt.WithMaxSize(maxMessageSize),
+		protofmt.WithFormat(format),
+		protofmt.WithOffset(format.Start()),
+	)
+
+	count := 0
+	for {
+		offset := dec.Offset()
+
+		dst := new(work.Work)
+
+		err := dec.Decode(dst)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, corrupted(errors.Wrap(err, "decoding work"))
+		}
+
+		if _, err := w.Write(indexEntry{offset: offset, id: indexID(dst)}.marshal()); err != nil {
+			return 0, errors.Wrap(err, "writing index")
+		}
+
+		count++
+	}
+
+	if err := w.Flush(); err != nil {
+		return 0, errors.Wrap(err, "writing index")
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, errors.Wrap(err, "closing index")
+	}
+
+	if err := os.Rename(tmpPath, indexPath(path)); err != nil {
+		return 0, errors.Wrap(err, "replacing index")
+	}
+
+	return count, nil
+}
+
+// RebuildIndex builds index of the section's work file from scratch.
+// It returns the number of works.
+func (s *FSStorage) RebuildIndex(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (int, error) {
+	return buildIndex(s.workPath(taskID, sectionID), s.maxMessageSize)
+}
+
+// Count returns the number of works from the index.
+// It returns ErrNoIndex if there is no index.
+func (s *FSStorage) Count(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) (int, error) {
+	info, err := os.Stat(indexPath(s.workPath(taskID, sectionID)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, ErrNoIndex
+		}
+		return 0, errors.Wrap(err, "stat index")
+	}
+
+	return int(info.Size() / indexEntrySize), nil
+}
+
+// Get looks up the work through the index, and reads only that record.
+func (s *FSStorage) Get(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID, workID uuid.UUID) (*work.Work, error) {
+	path := s.workPath(taskID, sectionID)
+
+	offset, err := lookupIndex(indexPath(path), workID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, format, err := openRecords(path, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening work path")
+	}
+	defer file.Close()
+
+	dst := new(work.Work)
+	if err := s.newDecoder(file, format, offset).Decode(dst); err != nil {
+		return nil, corrupted(errors.Wrap(err, "decoding work"))
+	}
+
+	return dst, nil
+}
+
+// lookupIndex returns offset of the first work with the id.
+func lookupIndex(path string, id uuid.UUID) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, ErrNoIndex
+		}
+		return 0, errors.Wrap(err, "opening index")
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	b := make([]byte, indexEntrySize)
+
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, work.ErrNotFound
+			}
+			return 0, errors.Wrap(err, "reading index")
+		}
+
+		if entry := unmarshalIndexEntry(b); entry.id == id {
+			return entry.offset, nil
+		}
+	}
+}
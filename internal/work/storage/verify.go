@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	protofmt "github.com/oneee-playground/r2d2-tester/internal/util/proto"
+	"github.com/oneee-playground/r2d2-tester/internal/work"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// Corruption is a damaged record found by Verify.
+type Corruption struct {
+	// File is the name of the file in section directory. e.g. work, tmpl.
+	File   string
+	Offset int64
+	Reason string
+}
+
+// SectionKey identifies a section directory in storage.
+type SectionKey struct {
+	TaskID    uuid.UUID
+	SectionID uuid.UUID
+}
+
+// Sections lists sections having work files, including compressed ones.
+func (s *FSStorage) Sections() ([]SectionKey, error) {
+	paths, err := filepath.Glob(filepath.Join(s.root, "*", "*", _filepathWorkPrefix))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding work files")
+	}
+
+	for _, known := range compressions {
+		compressed, err := filepath.Glob(filepath.Join(s.root, "*", "*", _filepathWorkPrefix+known.ext))
+		if err != nil {
+			return nil, errors.Wrap(err, "finding work files")
+		}
+		paths = append(paths, compressed...)
+	}
+
+	seen := make(map[SectionKey]bool)
+
+	keys := make([]SectionKey, 0, len(paths))
+	for _, path := range paths {
+		sectionDir := filepath.Dir(path)
+
+		taskID, err := uuid.Parse(filepath.Base(filepath.Dir(sectionDir)))
+		if err != nil {
+			continue
+		}
+
+		sectionID, err := uuid.Parse(filepath.Base(sectionDir))
+		if err != nil {
+			continue
+		}
+
+		key := SectionKey{TaskID: taskID, SectionID: sectionID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Verify reads every work and template of the section, and reports damaged records.
+// Records after a damaged one are still checked unless its length can't be trusted.
+func (s *FSStorage) Verify(ctx context.Context, taskID uuid.UUID, sectionID uuid.UUID) ([]Corruption, error) {
+	dir := filepath.Join(s.root, taskID.String(), sectionID.String())
+
+	files := []struct {
+		name   string
+		newMsg func() proto.Message
+	}{
+		{name: _filepathWorkPrefix, newMsg: func() proto.Message { return new(work.Work) }},
+		{name: _filepathTemplatePrefix, newMsg: func() proto.Message { return new(work.Template) }},
+	}
+
+	var corruptions []Corruption
+	for _, f := range files {
+		found, err := s.verifyFile(ctx, filepath.Join(dir, f.name), f.newMsg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "verifying %s", f.name)
+		}
+
+		for idx := range found {
+			found[idx].File = f.name
+		}
+		corruptions = append(corruptions, found...)
+	}
+
+	return corruptions, nil
+}
+
+func (s *FSStorage) verifyFile(ctx context.Context, path string, newMsg func() proto.Message) ([]Corruption, error) {
+	file, format, err := openRecords(path, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		var corrupt *protofmt.CorruptError
+		if errors.As(err, &corrupt) {
+			return []Corruption{{Offset: corrupt.Offset, Reason: corrupt.Reason}}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	dec := s.newDecoder(file, format, format.Start())
+
+	var corruptions []Corruption
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		err := dec.Decode(newMsg())
+		if errors.Is(err, io.EOF) {
+			return corruptions, nil
+		}
+
+		var (
+			corrupt  *protofmt.CorruptError
+			tooLarge *protofmt.TooLargeError
+		)
+		switch {
+		case err == nil:
+		case errors.As(err, &corrupt):
+			corruptions = append(corruptions, Corruption{Offset: corrupt.Offset, Reason: corrupt.Reason})
+		case errors.As(err, &tooLarge):
+			// Length is likely broken. Following records can't be found.
+			return append(corruptions, Corruption{Offset: tooLarge.Offset, Reason: tooLarge.Error()}), nil
+		case isDecompressionError(err):
+			// Offset is of decompressed content where it stopped.
+			return append(corruptions, Corruption{Offset: dec.Offset(), Reason: err.Error()}), nil
+		default:
+			return nil, err
+		}
+	}
+}
@@ -21,34 +21,1123 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type HeaderMatcher_Mode int32
+
+const (
+	HeaderMatcher_EXACT      HeaderMatcher_Mode = 0
+	HeaderMatcher_PREFIX     HeaderMatcher_Mode = 1
+	HeaderMatcher_REGEX      HeaderMatcher_Mode = 2
+	HeaderMatcher_PRESENT    HeaderMatcher_Mode = 3
+	HeaderMatcher_ABSENT     HeaderMatcher_Mode = 4
+	HeaderMatcher_ALL_VALUES HeaderMatcher_Mode = 5
+)
+
+// Enum value maps for HeaderMatcher_Mode.
+var (
+	HeaderMatcher_Mode_name = map[int32]string{
+		0: "EXACT",
+		1: "PREFIX",
+		2: "REGEX",
+		3: "PRESENT",
+		4: "ABSENT",
+		5: "ALL_VALUES",
+	}
+	HeaderMatcher_Mode_value = map[string]int32{
+		"EXACT":      0,
+		"PREFIX":     1,
+		"REGEX":      2,
+		"PRESENT":    3,
+		"ABSENT":     4,
+		"ALL_VALUES": 5,
+	}
+)
+
+func (x HeaderMatcher_Mode) Enum() *HeaderMatcher_Mode {
+	p := new(HeaderMatcher_Mode)
+	*p = x
+	return p
+}
+
+func (x HeaderMatcher_Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HeaderMatcher_Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_work_proto_enumTypes[0].Descriptor()
+}
+
+func (HeaderMatcher_Mode) Type() protoreflect.EnumType {
+	return &file_work_proto_enumTypes[0]
+}
+
+func (x HeaderMatcher_Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HeaderMatcher_Mode.Descriptor instead.
+func (HeaderMatcher_Mode) EnumDescriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type Assertion_Operator int32
+
+const (
+	Assertion_EQUALS     Assertion_Operator = 0
+	Assertion_NOT_EQUALS Assertion_Operator = 1
+	Assertion_REGEX      Assertion_Operator = 2
+	Assertion_RANGE      Assertion_Operator = 3
+	Assertion_TYPE_OF    Assertion_Operator = 4
+	Assertion_LENGTH     Assertion_Operator = 5
+	Assertion_CONTAINS   Assertion_Operator = 6
+	Assertion_ABSENT     Assertion_Operator = 7
+)
+
+// Enum value maps for Assertion_Operator.
+var (
+	Assertion_Operator_name = map[int32]string{
+		0: "EQUALS",
+		1: "NOT_EQUALS",
+		2: "REGEX",
+		3: "RANGE",
+		4: "TYPE_OF",
+		5: "LENGTH",
+		6: "CONTAINS",
+		7: "ABSENT",
+	}
+	Assertion_Operator_value = map[string]int32{
+		"EQUALS":     0,
+		"NOT_EQUALS": 1,
+		"REGEX":      2,
+		"RANGE":      3,
+		"TYPE_OF":    4,
+		"LENGTH":     5,
+		"CONTAINS":   6,
+		"ABSENT":     7,
+	}
+)
+
+func (x Assertion_Operator) Enum() *Assertion_Operator {
+	p := new(Assertion_Operator)
+	*p = x
+	return p
+}
+
+func (x Assertion_Operator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Assertion_Operator) Descriptor() protoreflect.EnumDescriptor {
+	return file_work_proto_enumTypes[1].Descriptor()
+}
+
+func (Assertion_Operator) Type() protoreflect.EnumType {
+	return &file_work_proto_enumTypes[1]
+}
+
+func (x Assertion_Operator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Assertion_Operator.Descriptor instead.
+func (Assertion_Operator) EnumDescriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{3, 0}
+}
+
+type Expected_BodyMatch int32
+
+const (
+	Expected_EXACT Expected_BodyMatch = 0
+	Expected_JSON  Expected_BodyMatch = 1
+)
+
+// Enum value maps for Expected_BodyMatch.
+var (
+	Expected_BodyMatch_name = map[int32]string{
+		0: "EXACT",
+		1: "JSON",
+	}
+	Expected_BodyMatch_value = map[string]int32{
+		"EXACT": 0,
+		"JSON":  1,
+	}
+)
+
+func (x Expected_BodyMatch) Enum() *Expected_BodyMatch {
+	p := new(Expected_BodyMatch)
+	*p = x
+	return p
+}
+
+func (x Expected_BodyMatch) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Expected_BodyMatch) Descriptor() protoreflect.EnumDescriptor {
+	return file_work_proto_enumTypes[2].Descriptor()
+}
+
+func (Expected_BodyMatch) Type() protoreflect.EnumType {
+	return &file_work_proto_enumTypes[2]
+}
+
+func (x Expected_BodyMatch) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Expected_BodyMatch.Descriptor instead.
+func (Expected_BodyMatch) EnumDescriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type Extraction_Source int32
+
+const (
+	Extraction_BODY   Extraction_Source = 0
+	Extraction_HEADER Extraction_Source = 1
+)
+
+// Enum value maps for Extraction_Source.
+var (
+	Extraction_Source_name = map[int32]string{
+		0: "BODY",
+		1: "HEADER",
+	}
+	Extraction_Source_value = map[string]int32{
+		"BODY":   0,
+		"HEADER": 1,
+	}
+)
+
+func (x Extraction_Source) Enum() *Extraction_Source {
+	p := new(Extraction_Source)
+	*p = x
+	return p
+}
+
+func (x Extraction_Source) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Extraction_Source) Descriptor() protoreflect.EnumDescriptor {
+	return file_work_proto_enumTypes[3].Descriptor()
+}
+
+func (Extraction_Source) Type() protoreflect.EnumType {
+	return &file_work_proto_enumTypes[3]
+}
+
+func (x Extraction_Source) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Extraction_Source.Descriptor instead.
+func (Extraction_Source) EnumDescriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{8, 0}
+}
+
 type Input struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Method  string            `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
-	Path    string            `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Headers map[string]string `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Body    []byte            `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Method  string            `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Path    string            `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Headers map[string]string `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body    []byte            `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *Input) Reset() {
+	*x = Input{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Input) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Input) ProtoMessage() {}
+
+func (x *Input) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Input.ProtoReflect.Descriptor instead.
+func (*Input) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Input) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Input) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Input) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Input) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+type HeaderMatcher struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key  string             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Mode HeaderMatcher_Mode `protobuf:"varint,2,opt,name=mode,proto3,enum=work.HeaderMatcher_Mode" json:"mode,omitempty"`
+	// Operand for EXACT, PREFIX and REGEX.
+	Value string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// Every value of the header in order, for ALL_VALUES.
+	Values []string `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *HeaderMatcher) Reset() {
+	*x = HeaderMatcher{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HeaderMatcher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeaderMatcher) ProtoMessage() {}
+
+func (x *HeaderMatcher) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeaderMatcher.ProtoReflect.Descriptor instead.
+func (*HeaderMatcher) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HeaderMatcher) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HeaderMatcher) GetMode() HeaderMatcher_Mode {
+	if x != nil {
+		return x.Mode
+	}
+	return HeaderMatcher_EXACT
+}
+
+func (x *HeaderMatcher) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *HeaderMatcher) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type CookieMatcher struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Any value matches if not given.
+	Value    *string `protobuf:"bytes,2,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	HttpOnly bool    `protobuf:"varint,3,opt,name=http_only,json=httpOnly,proto3" json:"http_only,omitempty"`
+	Secure   bool    `protobuf:"varint,4,opt,name=secure,proto3" json:"secure,omitempty"`
+	// Expects the cookie not to be set.
+	Absent bool `protobuf:"varint,5,opt,name=absent,proto3" json:"absent,omitempty"`
+}
+
+func (x *CookieMatcher) Reset() {
+	*x = CookieMatcher{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CookieMatcher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CookieMatcher) ProtoMessage() {}
+
+func (x *CookieMatcher) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CookieMatcher.ProtoReflect.Descriptor instead.
+func (*CookieMatcher) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CookieMatcher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CookieMatcher) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+func (x *CookieMatcher) GetHttpOnly() bool {
+	if x != nil {
+		return x.HttpOnly
+	}
+	return false
+}
+
+func (x *CookieMatcher) GetSecure() bool {
+	if x != nil {
+		return x.Secure
+	}
+	return false
+}
+
+func (x *CookieMatcher) GetAbsent() bool {
+	if x != nil {
+		return x.Absent
+	}
+	return false
+}
+
+type Assertion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// JSON pointer into the body.
+	Path     string             `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Operator Assertion_Operator `protobuf:"varint,2,opt,name=operator,proto3,enum=work.Assertion_Operator" json:"operator,omitempty"`
+	// JSON encoded operand for EQUALS, NOT_EQUALS and CONTAINS.
+	// Pattern for REGEX, and type name for TYPE_OF.
+	Value []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// Inclusive bounds for RANGE and LENGTH.
+	Min *float64 `protobuf:"fixed64,4,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *float64 `protobuf:"fixed64,5,opt,name=max,proto3,oneof" json:"max,omitempty"`
+}
+
+func (x *Assertion) Reset() {
+	*x = Assertion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Assertion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assertion) ProtoMessage() {}
+
+func (x *Assertion) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assertion.ProtoReflect.Descriptor instead.
+func (*Assertion) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Assertion) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Assertion) GetOperator() Assertion_Operator {
+	if x != nil {
+		return x.Operator
+	}
+	return Assertion_EQUALS
+}
+
+func (x *Assertion) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Assertion) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *Assertion) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+type Expected struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status     uint32             `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Headers    map[string]string  `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body       []byte             `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Assertions []*Assertion       `protobuf:"bytes,4,rep,name=assertions,proto3" json:"assertions,omitempty"`
+	BodyMatch  Expected_BodyMatch `protobuf:"varint,5,opt,name=body_match,json=bodyMatch,proto3,enum=work.Expected_BodyMatch" json:"body_match,omitempty"`
+	// JSON pointers excluded from JSON comparison. '*' matches any key or index.
+	IgnorePaths     []string         `protobuf:"bytes,6,rep,name=ignore_paths,json=ignorePaths,proto3" json:"ignore_paths,omitempty"`
+	UnorderedArrays bool             `protobuf:"varint,7,opt,name=unordered_arrays,json=unorderedArrays,proto3" json:"unordered_arrays,omitempty"`
+	HeaderMatchers  []*HeaderMatcher `protobuf:"bytes,8,rep,name=header_matchers,json=headerMatchers,proto3" json:"header_matchers,omitempty"`
+	// Allowed status codes. It overrides status if not empty.
+	Statuses []uint32 `protobuf:"varint,9,rep,packed,name=statuses,proto3" json:"statuses,omitempty"`
+	// Matched against cookies set by the response.
+	Cookies []*CookieMatcher `protobuf:"bytes,10,rep,name=cookies,proto3" json:"cookies,omitempty"`
+}
+
+func (x *Expected) Reset() {
+	*x = Expected{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Expected) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expected) ProtoMessage() {}
+
+func (x *Expected) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expected.ProtoReflect.Descriptor instead.
+func (*Expected) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Expected) GetStatus() uint32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Expected) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Expected) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *Expected) GetAssertions() []*Assertion {
+	if x != nil {
+		return x.Assertions
+	}
+	return nil
+}
+
+func (x *Expected) GetBodyMatch() Expected_BodyMatch {
+	if x != nil {
+		return x.BodyMatch
+	}
+	return Expected_EXACT
+}
+
+func (x *Expected) GetIgnorePaths() []string {
+	if x != nil {
+		return x.IgnorePaths
+	}
+	return nil
+}
+
+func (x *Expected) GetUnorderedArrays() bool {
+	if x != nil {
+		return x.UnorderedArrays
+	}
+	return false
+}
+
+func (x *Expected) GetHeaderMatchers() []*HeaderMatcher {
+	if x != nil {
+		return x.HeaderMatchers
+	}
+	return nil
+}
+
+func (x *Expected) GetStatuses() []uint32 {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *Expected) GetCookies() []*CookieMatcher {
+	if x != nil {
+		return x.Cookies
+	}
+	return nil
+}
+
+type TemplatedSchema struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Headers        map[string]string `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	BodySchema     []byte            `protobuf:"bytes,2,opt,name=body_schema,json=bodySchema,proto3" json:"body_schema,omitempty"`
+	HeaderMatchers []*HeaderMatcher  `protobuf:"bytes,3,rep,name=header_matchers,json=headerMatchers,proto3" json:"header_matchers,omitempty"`
+}
+
+func (x *TemplatedSchema) Reset() {
+	*x = TemplatedSchema{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplatedSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplatedSchema) ProtoMessage() {}
+
+func (x *TemplatedSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplatedSchema.ProtoReflect.Descriptor instead.
+func (*TemplatedSchema) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TemplatedSchema) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *TemplatedSchema) GetBodySchema() []byte {
+	if x != nil {
+		return x.BodySchema
+	}
+	return nil
+}
+
+func (x *TemplatedSchema) GetHeaderMatchers() []*HeaderMatcher {
+	if x != nil {
+		return x.HeaderMatchers
+	}
+	return nil
+}
+
+type StatusRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Inclusive bounds.
+	From   uint32           `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To     uint32           `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Schema *TemplatedSchema `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+}
+
+func (x *StatusRange) Reset() {
+	*x = StatusRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRange) ProtoMessage() {}
+
+func (x *StatusRange) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRange.ProtoReflect.Descriptor instead.
+func (*StatusRange) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatusRange) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *StatusRange) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *StatusRange) GetSchema() *TemplatedSchema {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+// Schema is looked up by exact status code first,
+// then by ranges in order, and then by status class.
+type Template struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          []byte                      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SchemaTable map[uint32]*TemplatedSchema `protobuf:"bytes,2,rep,name=schema_table,json=schemaTable,proto3" json:"schema_table,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Keyed by status class. e.g. 2 for 2xx.
+	ClassTable map[uint32]*TemplatedSchema `protobuf:"bytes,3,rep,name=class_table,json=classTable,proto3" json:"class_table,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RangeTable []*StatusRange              `protobuf:"bytes,4,rep,name=range_table,json=rangeTable,proto3" json:"range_table,omitempty"`
+}
+
+func (x *Template) Reset() {
+	*x = Template{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Template) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Template) ProtoMessage() {}
+
+func (x *Template) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Template.ProtoReflect.Descriptor instead.
+func (*Template) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Template) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Template) GetSchemaTable() map[uint32]*TemplatedSchema {
+	if x != nil {
+		return x.SchemaTable
+	}
+	return nil
+}
+
+func (x *Template) GetClassTable() map[uint32]*TemplatedSchema {
+	if x != nil {
+		return x.ClassTable
+	}
+	return nil
+}
+
+func (x *Template) GetRangeTable() []*StatusRange {
+	if x != nil {
+		return x.RangeTable
+	}
+	return nil
+}
+
+type Extraction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source Extraction_Source `protobuf:"varint,2,opt,name=source,proto3,enum=work.Extraction_Source" json:"source,omitempty"`
+	// JSON pointer into the body, or header key.
+	Key string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *Extraction) Reset() {
+	*x = Extraction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Extraction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Extraction) ProtoMessage() {}
+
+func (x *Extraction) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Extraction.ProtoReflect.Descriptor instead.
+func (*Extraction) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Extraction) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Extraction) GetSource() Extraction_Source {
+	if x != nil {
+		return x.Source
+	}
+	return Extraction_BODY
+}
+
+func (x *Extraction) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type RetryPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Zero retries until the deadline if there is one, and tries once otherwise.
+	MaxAttempts uint32               `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	Interval    *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Multiplies interval after each attempt. Interval stays the same if not given.
+	Backoff float64 `protobuf:"fixed64,3,opt,name=backoff,proto3" json:"backoff,omitempty"`
+	// Bounds total time spent on every attempt.
+	Deadline *durationpb.Duration `protobuf:"bytes,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() uint32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetBackoff() float64 {
+	if x != nil {
+		return x.Backoff
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetDeadline() *durationpb.Duration {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+type Work struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            []byte               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Input         *Input               `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	TemplateId    []byte               `protobuf:"bytes,3,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	ExpectedValue *Expected            `protobuf:"bytes,4,opt,name=expected_value,json=expectedValue,proto3,oneof" json:"expected_value,omitempty"`
+	Timeout       *durationpb.Duration `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Extractions   []*Extraction        `protobuf:"bytes,6,rep,name=extractions,proto3" json:"extractions,omitempty"`
+	// Clears session cookies before sending request.
+	ClearCookies bool `protobuf:"varint,7,opt,name=clear_cookies,json=clearCookies,proto3" json:"clear_cookies,omitempty"`
+	// Sends the request again until evaluation passes.
+	Retry *RetryPolicy `protobuf:"bytes,8,opt,name=retry,proto3,oneof" json:"retry,omitempty"`
+}
+
+func (x *Work) Reset() {
+	*x = Work{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_work_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Work) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Work) ProtoMessage() {}
+
+func (x *Work) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Work.ProtoReflect.Descriptor instead.
+func (*Work) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Work) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Work) GetInput() *Input {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *Work) GetTemplateId() []byte {
+	if x != nil {
+		return x.TemplateId
+	}
+	return nil
+}
+
+func (x *Work) GetExpectedValue() *Expected {
+	if x != nil {
+		return x.ExpectedValue
+	}
+	return nil
+}
+
+func (x *Work) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Work) GetExtractions() []*Extraction {
+	if x != nil {
+		return x.Extractions
+	}
+	return nil
+}
+
+func (x *Work) GetClearCookies() bool {
+	if x != nil {
+		return x.ClearCookies
+	}
+	return false
+}
+
+func (x *Work) GetRetry() *RetryPolicy {
+	if x != nil {
+		return x.Retry
+	}
+	return nil
+}
+
+type AssertionResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path     string             `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Operator Assertion_Operator `protobuf:"varint,2,opt,name=operator,proto3,enum=work.Assertion_Operator" json:"operator,omitempty"`
+	Passed   bool               `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+	// Why the assertion failed.
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *Input) Reset() {
-	*x = Input{}
+func (x *AssertionResult) Reset() {
+	*x = AssertionResult{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_work_proto_msgTypes[0]
+		mi := &file_work_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Input) String() string {
+func (x *AssertionResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Input) ProtoMessage() {}
+func (*AssertionResult) ProtoMessage() {}
 
-func (x *Input) ProtoReflect() protoreflect.Message {
-	mi := &file_work_proto_msgTypes[0]
+func (x *AssertionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,40 +1148,40 @@ func (x *Input) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Input.ProtoReflect.Descriptor instead.
-func (*Input) Descriptor() ([]byte, []int) {
-	return file_work_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use AssertionResult.ProtoReflect.Descriptor instead.
+func (*AssertionResult) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *Input) GetMethod() string {
+func (x *AssertionResult) GetPath() string {
 	if x != nil {
-		return x.Method
+		return x.Path
 	}
 	return ""
 }
 
-func (x *Input) GetPath() string {
+func (x *AssertionResult) GetOperator() Assertion_Operator {
 	if x != nil {
-		return x.Path
+		return x.Operator
 	}
-	return ""
+	return Assertion_EQUALS
 }
 
-func (x *Input) GetHeaders() map[string]string {
+func (x *AssertionResult) GetPassed() bool {
 	if x != nil {
-		return x.Headers
+		return x.Passed
 	}
-	return nil
+	return false
 }
 
-func (x *Input) GetBody() []byte {
+func (x *AssertionResult) GetMessage() string {
 	if x != nil {
-		return x.Body
+		return x.Message
 	}
-	return nil
+	return ""
 }
 
-type Expected struct {
+type Response struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -100,25 +1189,27 @@ type Expected struct {
 	Status  uint32            `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
 	Headers map[string]string `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Body    []byte            `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// Body was cut to fit into the record.
+	Truncated bool `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"`
 }
 
-func (x *Expected) Reset() {
-	*x = Expected{}
+func (x *Response) Reset() {
+	*x = Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_work_proto_msgTypes[1]
+		mi := &file_work_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Expected) String() string {
+func (x *Response) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Expected) ProtoMessage() {}
+func (*Response) ProtoMessage() {}
 
-func (x *Expected) ProtoReflect() protoreflect.Message {
-	mi := &file_work_proto_msgTypes[1]
+func (x *Response) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -129,58 +1220,80 @@ func (x *Expected) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Expected.ProtoReflect.Descriptor instead.
-func (*Expected) Descriptor() ([]byte, []int) {
-	return file_work_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Response.ProtoReflect.Descriptor instead.
+func (*Response) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *Expected) GetStatus() uint32 {
+func (x *Response) GetStatus() uint32 {
 	if x != nil {
 		return x.Status
 	}
 	return 0
 }
 
-func (x *Expected) GetHeaders() map[string]string {
+func (x *Response) GetHeaders() map[string]string {
 	if x != nil {
 		return x.Headers
 	}
 	return nil
 }
 
-func (x *Expected) GetBody() []byte {
+func (x *Response) GetBody() []byte {
 	if x != nil {
 		return x.Body
 	}
 	return nil
 }
 
-type TemplatedSchema struct {
+func (x *Response) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// Result is the outcome of an executed work.
+// Request, response and expected value are kept only for failures.
+// Templated works have the schema for the received status instead of expected value.
+type Result struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Headers    map[string]string `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	BodySchema []byte            `protobuf:"bytes,2,opt,name=body_schema,json=bodySchema,proto3" json:"body_schema,omitempty"`
+	WorkId     []byte               `protobuf:"bytes,1,opt,name=work_id,json=workId,proto3" json:"work_id,omitempty"`
+	SectionId  []byte               `protobuf:"bytes,2,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	Passed     bool                 `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+	Status     uint32               `protobuf:"varint,4,opt,name=status,proto3" json:"status,omitempty"`
+	Latency    *durationpb.Duration `protobuf:"bytes,5,opt,name=latency,proto3" json:"latency,omitempty"`
+	Attempts   uint32               `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Assertions []*AssertionResult   `protobuf:"bytes,7,rep,name=assertions,proto3" json:"assertions,omitempty"`
+	// Category of the failure. e.g. status, body, timeout.
+	Failure        string           `protobuf:"bytes,8,opt,name=failure,proto3" json:"failure,omitempty"`
+	Error          string           `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Request        *Input           `protobuf:"bytes,10,opt,name=request,proto3" json:"request,omitempty"`
+	Response       *Response        `protobuf:"bytes,11,opt,name=response,proto3" json:"response,omitempty"`
+	Expected       *Expected        `protobuf:"bytes,12,opt,name=expected,proto3" json:"expected,omitempty"`
+	ExpectedSchema *TemplatedSchema `protobuf:"bytes,13,opt,name=expected_schema,json=expectedSchema,proto3" json:"expected_schema,omitempty"`
 }
 
-func (x *TemplatedSchema) Reset() {
-	*x = TemplatedSchema{}
+func (x *Result) Reset() {
+	*x = Result{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_work_proto_msgTypes[2]
+		mi := &file_work_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TemplatedSchema) String() string {
+func (x *Result) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TemplatedSchema) ProtoMessage() {}
+func (*Result) ProtoMessage() {}
 
-func (x *TemplatedSchema) ProtoReflect() protoreflect.Message {
-	mi := &file_work_proto_msgTypes[2]
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_work_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,155 +1304,98 @@ func (x *TemplatedSchema) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TemplatedSchema.ProtoReflect.Descriptor instead.
-func (*TemplatedSchema) Descriptor() ([]byte, []int) {
-	return file_work_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_work_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *TemplatedSchema) GetHeaders() map[string]string {
+func (x *Result) GetWorkId() []byte {
 	if x != nil {
-		return x.Headers
+		return x.WorkId
 	}
 	return nil
 }
 
-func (x *TemplatedSchema) GetBodySchema() []byte {
+func (x *Result) GetSectionId() []byte {
 	if x != nil {
-		return x.BodySchema
+		return x.SectionId
 	}
 	return nil
 }
 
-type Template struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Id          []byte                      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	SchemaTable map[uint32]*TemplatedSchema `protobuf:"bytes,2,rep,name=schema_table,json=schemaTable,proto3" json:"schema_table,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-}
-
-func (x *Template) Reset() {
-	*x = Template{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_work_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Result) GetPassed() bool {
+	if x != nil {
+		return x.Passed
 	}
+	return false
 }
 
-func (x *Template) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Template) ProtoMessage() {}
-
-func (x *Template) ProtoReflect() protoreflect.Message {
-	mi := &file_work_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *Result) GetStatus() uint32 {
+	if x != nil {
+		return x.Status
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Template.ProtoReflect.Descriptor instead.
-func (*Template) Descriptor() ([]byte, []int) {
-	return file_work_proto_rawDescGZIP(), []int{3}
+	return 0
 }
 
-func (x *Template) GetId() []byte {
+func (x *Result) GetLatency() *durationpb.Duration {
 	if x != nil {
-		return x.Id
+		return x.Latency
 	}
 	return nil
 }
 
-func (x *Template) GetSchemaTable() map[uint32]*TemplatedSchema {
+func (x *Result) GetAttempts() uint32 {
 	if x != nil {
-		return x.SchemaTable
+		return x.Attempts
 	}
-	return nil
-}
-
-type Work struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Id            []byte               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Input         *Input               `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
-	TemplateId    []byte               `protobuf:"bytes,3,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
-	ExpectedValue *Expected            `protobuf:"bytes,4,opt,name=expected_value,json=expectedValue,proto3,oneof" json:"expected_value,omitempty"`
-	Timeout       *durationpb.Duration `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	return 0
 }
 
-func (x *Work) Reset() {
-	*x = Work{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_work_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Result) GetAssertions() []*AssertionResult {
+	if x != nil {
+		return x.Assertions
 	}
+	return nil
 }
 
-func (x *Work) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Work) ProtoMessage() {}
-
-func (x *Work) ProtoReflect() protoreflect.Message {
-	mi := &file_work_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *Result) GetFailure() string {
+	if x != nil {
+		return x.Failure
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Work.ProtoReflect.Descriptor instead.
-func (*Work) Descriptor() ([]byte, []int) {
-	return file_work_proto_rawDescGZIP(), []int{4}
+	return ""
 }
 
-func (x *Work) GetId() []byte {
+func (x *Result) GetError() string {
 	if x != nil {
-		return x.Id
+		return x.Error
 	}
-	return nil
+	return ""
 }
 
-func (x *Work) GetInput() *Input {
+func (x *Result) GetRequest() *Input {
 	if x != nil {
-		return x.Input
+		return x.Request
 	}
 	return nil
 }
 
-func (x *Work) GetTemplateId() []byte {
+func (x *Result) GetResponse() *Response {
 	if x != nil {
-		return x.TemplateId
+		return x.Response
 	}
 	return nil
 }
 
-func (x *Work) GetExpectedValue() *Expected {
+func (x *Result) GetExpected() *Expected {
 	if x != nil {
-		return x.ExpectedValue
+		return x.Expected
 	}
 	return nil
 }
 
-func (x *Work) GetTimeout() *durationpb.Duration {
+func (x *Result) GetExpectedSchema() *TemplatedSchema {
 	if x != nil {
-		return x.Timeout
+		return x.ExpectedSchema
 	}
 	return nil
 }
@@ -361,56 +1417,222 @@ var file_work_proto_rawDesc = []byte{
 	0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
 	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
 	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa9, 0x01, 0x0a,
-	0x08, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x35, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x1a, 0x3a, 0x0a, 0x0c,
-	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
-	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
-	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xac, 0x01, 0x0a, 0x0f, 0x54, 0x65, 0x6d,
-	0x70, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x3c, 0x0a, 0x07,
-	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e,
-	0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x63,
-	0x68, 0x65, 0x6d, 0x61, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6f,
-	0x64, 0x79, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0a, 0x62, 0x6f, 0x64, 0x79, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x1a, 0x3a, 0x0a, 0x0c, 0x48,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb5, 0x01, 0x0a, 0x08, 0x54, 0x65, 0x6d, 0x70,
-	0x6c, 0x61, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x42, 0x0a, 0x0c, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x74,
-	0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x6f, 0x72,
-	0x6b, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x73, 0x63, 0x68,
-	0x65, 0x6d, 0x61, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x1a, 0x55, 0x0a, 0x10, 0x53, 0x63, 0x68, 0x65,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xd0, 0x01, 0x0a,
+	0x0d, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x2c, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x72, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x04,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x58, 0x41, 0x43, 0x54, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x50, 0x52, 0x45, 0x46, 0x49, 0x58, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x52,
+	0x45, 0x47, 0x45, 0x58, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e,
+	0x54, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x42, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x04, 0x12,
+	0x0e, 0x0a, 0x0a, 0x41, 0x4c, 0x4c, 0x5f, 0x56, 0x41, 0x4c, 0x55, 0x45, 0x53, 0x10, 0x05, 0x22,
+	0x95, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x1b, 0x0a, 0x09, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x74, 0x74, 0x70, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73,
+	0x65, 0x63, 0x75, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x9a, 0x02, 0x0a, 0x09, 0x41, 0x73, 0x73, 0x65,
+	0x72, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x15, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x00, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03,
+	0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x03, 0x6d, 0x61, 0x78,
+	0x88, 0x01, 0x01, 0x22, 0x6f, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x0a, 0x0a, 0x06, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x53, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x4e,
+	0x4f, 0x54, 0x5f, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x53, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x52,
+	0x45, 0x47, 0x45, 0x58, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10,
+	0x03, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4f, 0x46, 0x10, 0x04, 0x12, 0x0a,
+	0x0a, 0x06, 0x4c, 0x45, 0x4e, 0x47, 0x54, 0x48, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x4f,
+	0x4e, 0x54, 0x41, 0x49, 0x4e, 0x53, 0x10, 0x06, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x42, 0x53, 0x45,
+	0x4e, 0x54, 0x10, 0x07, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x06, 0x0a, 0x04,
+	0x5f, 0x6d, 0x61, 0x78, 0x22, 0x8c, 0x04, 0x0a, 0x08, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x35, 0x0a, 0x07, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x77, 0x6f, 0x72,
+	0x6b, 0x2e, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x62, 0x6f, 0x64, 0x79, 0x12, 0x2f, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e,
+	0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x72,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x37, 0x0a, 0x0a, 0x62, 0x6f, 0x64, 0x79, 0x5f, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
+	0x2e, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x2e, 0x42, 0x6f, 0x64, 0x79, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x09, 0x62, 0x6f, 0x64, 0x79, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21,
+	0x0a, 0x0c, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x50, 0x61, 0x74, 0x68,
+	0x73, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x6e, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x61,
+	0x72, 0x72, 0x61, 0x79, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x75, 0x6e, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x41, 0x72, 0x72, 0x61, 0x79, 0x73, 0x12, 0x3c, 0x0a, 0x0f,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x52, 0x0e, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65,
+	0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x43,
+	0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x52, 0x07, 0x63, 0x6f,
+	0x6f, 0x6b, 0x69, 0x65, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x20, 0x0a, 0x09, 0x42, 0x6f, 0x64, 0x79, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x09,
+	0x0a, 0x05, 0x45, 0x58, 0x41, 0x43, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4a, 0x53, 0x4f,
+	0x4e, 0x10, 0x01, 0x22, 0xea, 0x01, 0x0a, 0x0f, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x3c, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6f, 0x64, 0x79, 0x5f, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x62, 0x6f, 0x64, 0x79,
+	0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x3c, 0x0a, 0x0f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x72, 0x52, 0x0e, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x72, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x60, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x02, 0x74, 0x6f, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x22, 0x80, 0x03, 0x0a, 0x08, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x42, 0x0a, 0x0c, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x3f, 0x0a, 0x0b, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x54,
+	0x61, 0x62, 0x6c, 0x65, 0x12, 0x32, 0x0a, 0x0b, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0a, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x1a, 0x55, 0x0a, 0x10, 0x53, 0x63, 0x68, 0x65,
 	0x6d, 0x61, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
 	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b,
 	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
 	0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x63,
-	0x68, 0x65, 0x6d, 0x61, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0xde, 0x01, 0x0a, 0x04, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x49,
-	0x6e, 0x70, 0x75, 0x74, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x74,
-	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x0a, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x0e,
-	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x45, 0x78, 0x70, 0x65,
-	0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0d, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65,
-	0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x42, 0x11, 0x0a,
-	0x0f, 0x5f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x42, 0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x68, 0x65, 0x6d, 0x61, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x54, 0x0a, 0x0f, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x83, 0x01, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x72, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x1e, 0x0a, 0x06, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x44, 0x59, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52, 0x10, 0x01, 0x22, 0xb8, 0x01, 0x0a, 0x0b,
+	0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6d,
+	0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x35,
+	0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12,
+	0x35, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x65,
+	0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0xef, 0x02, 0x0a, 0x04, 0x57, 0x6f, 0x72, 0x6b, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x21, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x05, 0x69, 0x6e, 0x70,
+	0x75, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x0e, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x2e, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0d, 0x65,
+	0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x12, 0x32, 0x0a, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
+	0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x65, 0x78, 0x74,
+	0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6c, 0x65, 0x61,
+	0x72, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x73, 0x12, 0x2c, 0x0a,
+	0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77,
+	0x6f, 0x72, 0x6b, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x48,
+	0x01, 0x52, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x22, 0x8d, 0x01, 0x0a, 0x0f, 0x41, 0x73, 0x73,
+	0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x34, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x6f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xc7, 0x01, 0x0a, 0x08, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x35, 0x0a,
+	0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x75, 0x6e,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x72, 0x75,
+	0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xe7, 0x03, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x77, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x33, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x35, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x6f, 0x72,
+	0x6b, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x25, 0x0a,
+	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x0f,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x0e, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x42, 0x08, 0x5a, 0x06,
+	0x2e, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -425,33 +1647,73 @@ func file_work_proto_rawDescGZIP() []byte {
 	return file_work_proto_rawDescData
 }
 
-var file_work_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_work_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_work_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_work_proto_goTypes = []interface{}{
-	(*Input)(nil),               // 0: work.Input
-	(*Expected)(nil),            // 1: work.Expected
-	(*TemplatedSchema)(nil),     // 2: work.TemplatedSchema
-	(*Template)(nil),            // 3: work.Template
-	(*Work)(nil),                // 4: work.Work
-	nil,                         // 5: work.Input.HeadersEntry
-	nil,                         // 6: work.Expected.HeadersEntry
-	nil,                         // 7: work.TemplatedSchema.HeadersEntry
-	nil,                         // 8: work.Template.SchemaTableEntry
-	(*durationpb.Duration)(nil), // 9: google.protobuf.Duration
+	(HeaderMatcher_Mode)(0),     // 0: work.HeaderMatcher.Mode
+	(Assertion_Operator)(0),     // 1: work.Assertion.Operator
+	(Expected_BodyMatch)(0),     // 2: work.Expected.BodyMatch
+	(Extraction_Source)(0),      // 3: work.Extraction.Source
+	(*Input)(nil),               // 4: work.Input
+	(*HeaderMatcher)(nil),       // 5: work.HeaderMatcher
+	(*CookieMatcher)(nil),       // 6: work.CookieMatcher
+	(*Assertion)(nil),           // 7: work.Assertion
+	(*Expected)(nil),            // 8: work.Expected
+	(*TemplatedSchema)(nil),     // 9: work.TemplatedSchema
+	(*StatusRange)(nil),         // 10: work.StatusRange
+	(*Template)(nil),            // 11: work.Template
+	(*Extraction)(nil),          // 12: work.Extraction
+	(*RetryPolicy)(nil),         // 13: work.RetryPolicy
+	(*Work)(nil),                // 14: work.Work
+	(*AssertionResult)(nil),     // 15: work.AssertionResult
+	(*Response)(nil),            // 16: work.Response
+	(*Result)(nil),              // 17: work.Result
+	nil,                         // 18: work.Input.HeadersEntry
+	nil,                         // 19: work.Expected.HeadersEntry
+	nil,                         // 20: work.TemplatedSchema.HeadersEntry
+	nil,                         // 21: work.Template.SchemaTableEntry
+	nil,                         // 22: work.Template.ClassTableEntry
+	nil,                         // 23: work.Response.HeadersEntry
+	(*durationpb.Duration)(nil), // 24: google.protobuf.Duration
 }
 var file_work_proto_depIdxs = []int32{
-	5, // 0: work.Input.headers:type_name -> work.Input.HeadersEntry
-	6, // 1: work.Expected.headers:type_name -> work.Expected.HeadersEntry
-	7, // 2: work.TemplatedSchema.headers:type_name -> work.TemplatedSchema.HeadersEntry
-	8, // 3: work.Template.schema_table:type_name -> work.Template.SchemaTableEntry
-	0, // 4: work.Work.input:type_name -> work.Input
-	1, // 5: work.Work.expected_value:type_name -> work.Expected
-	9, // 6: work.Work.timeout:type_name -> google.protobuf.Duration
-	2, // 7: work.Template.SchemaTableEntry.value:type_name -> work.TemplatedSchema
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	18, // 0: work.Input.headers:type_name -> work.Input.HeadersEntry
+	0,  // 1: work.HeaderMatcher.mode:type_name -> work.HeaderMatcher.Mode
+	1,  // 2: work.Assertion.operator:type_name -> work.Assertion.Operator
+	19, // 3: work.Expected.headers:type_name -> work.Expected.HeadersEntry
+	7,  // 4: work.Expected.assertions:type_name -> work.Assertion
+	2,  // 5: work.Expected.body_match:type_name -> work.Expected.BodyMatch
+	5,  // 6: work.Expected.header_matchers:type_name -> work.HeaderMatcher
+	6,  // 7: work.Expected.cookies:type_name -> work.CookieMatcher
+	20, // 8: work.TemplatedSchema.headers:type_name -> work.TemplatedSchema.HeadersEntry
+	5,  // 9: work.TemplatedSchema.header_matchers:type_name -> work.HeaderMatcher
+	9,  // 10: work.StatusRange.schema:type_name -> work.TemplatedSchema
+	21, // 11: work.Template.schema_table:type_name -> work.Template.SchemaTableEntry
+	22, // 12: work.Template.class_table:type_name -> work.Template.ClassTableEntry
+	10, // 13: work.Template.range_table:type_name -> work.StatusRange
+	3,  // 14: work.Extraction.source:type_name -> work.Extraction.Source
+	24, // 15: work.RetryPolicy.interval:type_name -> google.protobuf.Duration
+	24, // 16: work.RetryPolicy.deadline:type_name -> google.protobuf.Duration
+	4,  // 17: work.Work.input:type_name -> work.Input
+	8,  // 18: work.Work.expected_value:type_name -> work.Expected
+	24, // 19: work.Work.timeout:type_name -> google.protobuf.Duration
+	12, // 20: work.Work.extractions:type_name -> work.Extraction
+	13, // 21: work.Work.retry:type_name -> work.RetryPolicy
+	1,  // 22: work.AssertionResult.operator:type_name -> work.Assertion.Operator
+	23, // 23: work.Response.headers:type_name -> work.Response.HeadersEntry
+	24, // 24: work.Result.latency:type_name -> google.protobuf.Duration
+	15, // 25: work.Result.assertions:type_name -> work.AssertionResult
+	4,  // 26: work.Result.request:type_name -> work.Input
+	16, // 27: work.Result.response:type_name -> work.Response
+	8,  // 28: work.Result.expected:type_name -> work.Expected
+	9,  // 29: work.Result.expected_schema:type_name -> work.TemplatedSchema
+	9,  // 30: work.Template.SchemaTableEntry.value:type_name -> work.TemplatedSchema
+	9,  // 31: work.Template.ClassTableEntry.value:type_name -> work.TemplatedSchema
+	32, // [32:32] is the sub-list for method output_type
+	32, // [32:32] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_work_proto_init() }
@@ -473,7 +1735,7 @@ func file_work_proto_init() {
 			}
 		}
 		file_work_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Expected); i {
+			switch v := v.(*HeaderMatcher); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -485,7 +1747,7 @@ func file_work_proto_init() {
 			}
 		}
 		file_work_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TemplatedSchema); i {
+			switch v := v.(*CookieMatcher); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -497,7 +1759,7 @@ func file_work_proto_init() {
 			}
 		}
 		file_work_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Template); i {
+			switch v := v.(*Assertion); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -509,6 +1771,78 @@ func file_work_proto_init() {
 			}
 		}
 		file_work_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Expected); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplatedSchema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Template); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Extraction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Work); i {
 			case 0:
 				return &v.state
@@ -520,20 +1854,59 @@ func file_work_proto_init() {
 				return nil
 			}
 		}
+		file_work_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssertionResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_work_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_work_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_work_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	file_work_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_work_proto_msgTypes[10].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_work_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      4,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_work_proto_goTypes,
 		DependencyIndexes: file_work_proto_depIdxs,
+		EnumInfos:         file_work_proto_enumTypes,
 		MessageInfos:      file_work_proto_msgTypes,
 	}.Build()
 	File_work_proto = out.File
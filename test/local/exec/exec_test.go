@@ -7,11 +7,13 @@ import (
 	"os/signal"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/google/uuid"
 	influxdb2 "github.com/influxdata/influxdb-client-go"
+	"github.com/oneee-playground/r2d2-tester/internal/event"
 	"github.com/oneee-playground/r2d2-tester/internal/exec"
 	"github.com/oneee-playground/r2d2-tester/internal/job"
 	"github.com/oneee-playground/r2d2-tester/internal/metric"
@@ -128,8 +130,8 @@ func (s *ExecSuite) TestExecutor() {
 	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	err := exec.NewExecutor(opts).Execute(ctx, job)
-	s.NoError(err)
+	result := exec.NewExecutor(opts).Execute(ctx, job)
+	s.Truef(result.Success, "failure: %+v", result.Failure)
 
 	s.docker.Close()
 	// This will not close the underlying idle connections.
@@ -138,3 +140,77 @@ func (s *ExecSuite) TestExecutor() {
 	// Yeah it is dangerous and should be used carefully.
 	s.influxClient.Close()
 }
+
+func (s *ExecSuite) TestHooks() {
+	opts := exec.ExecOpts{
+		Log:           s.log,
+		HTTPClient:    s.httpClient,
+		WorkStorage:   s.workStorage,
+		Docker:        s.docker,
+		MetricStorage: s.metricStroage,
+		ExecNetwork:   s.execNetwork,
+		TestNetwork:   s.testNetwork,
+	}
+
+	job := job.Job{
+		TaskID: uuid.Nil,
+		Resources: []job.Resource{
+			{
+				Name:      "app",
+				Port:      4000,
+				CPU:       1,
+				Memory:    100 * 1024 * 1024,
+				IsPrimary: true,
+			},
+			{
+				Image:  "nginx:alpine",
+				Name:   "db",
+				Port:   80,
+				CPU:    1,
+				Memory: 100 * 1024 * 1024,
+			},
+		},
+		Sections: []job.Section{
+			{
+				ID:   uuid.Nil,
+				Type: job.TypeScenario,
+				Setup: []job.Hook{{
+					Resource: "db",
+					Command:  []string{"sh", "-c", "echo seeding failed >&2; exit 3"},
+				}},
+			},
+			{
+				ID:   uuid.Nil,
+				Type: job.TypeScenario,
+				Setup: []job.Hook{{
+					Resource: "db",
+					Command:  []string{"sleep", "60"},
+					Timeout:  time.Second,
+				}},
+			},
+		},
+		Submission: job.Submission{
+			ID:         uuid.Nil,
+			Repository: "oneee-playground/hello-docker",
+			CommitHash: "4d699f27bf2b5e67b3bc0a6195ef75ad6ac04112",
+		},
+	}
+
+	result := exec.NewExecutor(opts).Execute(context.Background(), job)
+	s.False(result.Success)
+
+	if s.Len(result.Sections, 2) {
+		failed := result.Sections[0].Failure
+		if s.NotNil(failed) {
+			s.Equal(event.StageSetup, failed.Stage)
+			s.Contains(failed.Message, "exited with code 3")
+			s.Contains(failed.Message, "seeding failed")
+		}
+
+		timedOut := result.Sections[1].Failure
+		if s.NotNil(timedOut) {
+			s.Equal(event.StageSetup, timedOut.Stage)
+			s.Contains(timedOut.Message, "deadline exceeded")
+		}
+	}
+}